@@ -0,0 +1,305 @@
+package paging
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrCacheNotSupported is returned by the CachedStore's PaginateCursor method
+// when the cached store makes its own cursors: they can't be replayed from
+// the cache.
+var ErrCacheNotSupported = errors.New("store does not support caching")
+
+// -----------------------------------------------------------------------------
+// Cache interface
+// -----------------------------------------------------------------------------
+
+// Cache is a cache of serialized pages, safe for concurrent use.
+type Cache interface {
+	// Get returns the value of key, false when it's missing or expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the value of key for ttl (without expiration when zero),
+	// tagged with tags.
+	Set(key string, value []byte, ttl time.Duration, tags ...string)
+	// Invalidate removes the values tagged with tag.
+	Invalidate(tag string)
+}
+
+// -----------------------------------------------------------------------------
+// Cached Store
+// -----------------------------------------------------------------------------
+
+// CachedStore is a store caching the pages of another store. Pages are keyed
+// on the query identity, its filters and sort, the items type, and the page
+// limit, offset or cursor, and stored as JSON: items must round-trip through
+// encoding/json. The cached store doesn't support Seek.
+type CachedStore struct {
+	store Store
+	cache Cache
+	key   string
+	ttl   time.Duration
+	tags  []string
+}
+
+// NewCachedStore returns a new cached store instance. key identifies the
+// query of store, pages are cached for ttl (without expiration when zero)
+// and tagged with tags for Cache.Invalidate.
+func NewCachedStore(store Store, cache Cache, key string, ttl time.Duration, tags ...string) (*CachedStore, error) {
+	return &CachedStore{
+		store: store,
+		cache: cache,
+		key:   key,
+		ttl:   ttl,
+		tags:  tags,
+	}, nil
+}
+
+// Filter returns a new store caching the pages of the filtered store.
+func (s *CachedStore) Filter(filters Filters) (Store, error) {
+	store, err := ApplyFilters(s.store, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(store, "filter:"+string(identity)), nil
+}
+
+// Sort returns a new store caching the pages of the sorted store.
+func (s *CachedStore) Sort(fieldName string, reverse bool) (Store, error) {
+	sorter, ok := s.store.(Sorter)
+	if !ok {
+		return nil, ErrSortNotSupported
+	}
+
+	store, err := sorter.Sort(fieldName, reverse)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(store, fmt.Sprintf("sort:%s:%s", fieldName, cursorDirection(reverse))), nil
+}
+
+// wrap returns a copy of the store caching the pages of store, identified
+// by the query identity followed by suffix.
+func (s *CachedStore) wrap(store Store, suffix string) *CachedStore {
+	c := *s
+	c.store = store
+	c.key = s.key + "|" + suffix
+	return &c
+}
+
+// PaginateOffset paginates items from the cache, or from the store when
+// they aren't cached.
+func (s *CachedStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	key := fmt.Sprintf("%s|%s|offset:%d:%d", s.key, reflect.TypeOf(items), limit, offset)
+
+	var page cachedPage
+	err := s.cached(key, items, &page, func() error {
+		return s.store.PaginateOffset(items, limit, offset, &page.Count)
+	})
+	if err != nil {
+		return err
+	}
+
+	*count = page.Count
+	return nil
+}
+
+// PaginateCursor paginates items from the cache, or from the store when
+// they aren't cached. It returns ErrCacheNotSupported when the store is a
+// NextCursorer.
+func (s *CachedStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	if _, ok := s.store.(NextCursorer); ok {
+		return ErrCacheNotSupported
+	}
+
+	c, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s|%s|cursor:%d:%s:%s:%s", s.key, reflect.TypeOf(items), limit, c, fieldName, cursorDirection(reverse))
+
+	var page cachedPage
+	err = s.cached(key, items, &page, func() error {
+		return s.store.PaginateCursor(items, limit, cursor, fieldName, reverse, &page.HasNext)
+	})
+	if err != nil {
+		return err
+	}
+
+	*hasnext = page.HasNext
+	return nil
+}
+
+// cachedPage is a page stored in the cache.
+type cachedPage struct {
+	Items   json.RawMessage `json:"items"`
+	Count   int64           `json:"count,omitempty"`
+	HasNext bool            `json:"hasnext,omitempty"`
+}
+
+// cached decodes the page of key into page and items, or runs paginate and
+// caches its page.
+func (s *CachedStore) cached(key string, items interface{}, page *cachedPage, paginate func() error) error {
+	if value, ok := s.cache.Get(key); ok {
+		if err := json.Unmarshal(value, page); err != nil {
+			return err
+		}
+		return decodeItems(page.Items, items)
+	}
+
+	if err := paginate(); err != nil {
+		return err
+	}
+
+	var err error
+	if page.Items, err = json.Marshal(items); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+
+	s.cache.Set(key, value, s.ttl, s.tags...)
+	return nil
+}
+
+// decodeItems decodes data into items, a pointer to a slice, replacing its
+// elements.
+func decodeItems(data []byte, items interface{}) error {
+	slice, err := ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	// json.Unmarshal decodes into the existing elements of the slice
+	slice.Set(reflect.Zero(slice.Type()))
+
+	return json.Unmarshal(data, items)
+}
+
+// -----------------------------------------------------------------------------
+// LRU Cache
+// -----------------------------------------------------------------------------
+
+// LRUCache is an in-memory Cache evicting the least recently used values
+// beyond its size.
+type LRUCache struct {
+	size    int
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	tags    map[string]map[string]struct{}
+	now     func() time.Time
+}
+
+// lruEntry is a value of the LRUCache.
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+	tags    []string
+}
+
+// NewLRUCache returns a new LRUCache instance holding up to size values,
+// without limit when size isn't positive.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		tags:    map[string]map[string]struct{}{},
+		now:     time.Now,
+	}
+}
+
+// Get returns the value of key, false when it's missing or expired.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores the value of key for ttl (without expiration when zero),
+// tagged with tags, evicting the least recently used value when full.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	entry := &lruEntry{key: key, value: value, tags: tags}
+	if ttl > 0 {
+		entry.expires = c.now().Add(ttl)
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = map[string]struct{}{}
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+
+	for c.size > 0 && c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate removes the values tagged with tag.
+func (c *LRUCache) Invalidate(tag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.tags[tag] {
+		c.remove(c.entries[key])
+	}
+}
+
+// Len returns the number of values, expired ones included.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// remove removes an entry and its tags.
+func (c *LRUCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*lruEntry)
+	delete(c.entries, entry.key)
+
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
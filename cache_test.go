@@ -0,0 +1,115 @@
+package paging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedStore(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	cache := NewLRUCache(100)
+	cached, err := NewCachedStore(store, cache, "users", time.Minute, "users")
+	is.NoError(err)
+
+	users := []User{}
+	p, err := NewOffsetPaginatorFromPageRequest(cached, PageRequest{Limit: 10}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+	is.Equal(1, users[0].ID)
+	is.Equal(int64(100), p.Count)
+
+	// the page is served from the cache
+	is.NoError(db.Delete(&User{ID: 1}).Error)
+	users = []User{{Name: "stale"}}
+	is.NoError(p.Page(&users))
+	is.Len(users, 10)
+	is.Equal(1, users[0].ID)
+	is.Equal("user-1", users[0].Name)
+	is.Equal(int64(100), p.Count)
+
+	cursor, err := NewCursorPaginatorFromPageRequest(cached, PageRequest{Limit: 10, Cursor: int64(5)}, nil)
+	is.NoError(err)
+	is.NoError(cursor.Page(&users))
+	is.Equal(6, users[0].ID)
+	is.True(cursor.HasNext())
+	is.Equal(2, cache.Len())
+
+	// filters and sort are part of the key
+	options := NewOptions()
+	options.SortFields = []string{"id"}
+
+	filtered, err := NewOffsetPaginatorFromPageRequest(cached, PageRequest{
+		Limit:     10,
+		Sort:      "id",
+		Direction: SortDesc,
+		Filters:   Filters{{DBName: "number", Operator: FilterLessThanOrEqual, Value: 50}},
+	}, options)
+	is.NoError(err)
+	is.NoError(filtered.Page(&users))
+	is.Equal(50, users[0].ID)
+	is.Equal(int64(49), filtered.Count)
+	is.Equal(3, cache.Len())
+
+	// the items type is part of the key, the page is fetched from the store
+	pointers := []*User{}
+	is.NoError(p.Page(&pointers))
+	is.Equal(2, pointers[0].ID)
+	is.Equal(4, cache.Len())
+
+	cache.Invalidate("users")
+	is.Equal(0, cache.Len())
+	is.NoError(p.Page(&users))
+	is.Equal(2, users[0].ID)
+	is.Equal(int64(99), p.Count)
+}
+
+func TestLRUCache(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Now()
+	cache := NewLRUCache(2)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("1"), 0, "letters")
+	cache.Set("b", []byte("2"), time.Second)
+	_, ok := cache.Get("a")
+	is.True(ok)
+
+	// b is the least recently used
+	cache.Set("c", []byte("3"), 0, "letters")
+	_, ok = cache.Get("b")
+	is.False(ok)
+
+	cache.Set("b", []byte("2"), time.Second)
+	now = now.Add(time.Second)
+	_, ok = cache.Get("b")
+	is.False(ok)
+	is.Equal(1, cache.Len())
+
+	value, ok := cache.Get("c")
+	is.True(ok)
+	is.Equal([]byte("3"), value)
+
+	cache.Invalidate("letters")
+	_, ok = cache.Get("c")
+	is.False(ok)
+	is.Equal(0, cache.Len())
+
+	// without size, nothing is evicted
+	cache = NewLRUCache(0)
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Set(key, []byte(key), 0)
+	}
+	value, ok = cache.Get("a")
+	is.True(ok)
+	is.Equal([]byte("a"), value)
+	is.Equal(3, cache.Len())
+}
@@ -0,0 +1,163 @@
+package paging
+
+import (
+	"errors"
+
+	"github.com/guregu/null"
+)
+
+// ErrInvalidConnectionArgs is returned by NewConnectionPaginator when first
+// and last, after and last or before and first are set together.
+var ErrInvalidConnectionArgs = errors.New("invalid connection arguments")
+
+// ErrConnectionNotSupported is returned by NewConnectionPaginator when the
+// store makes its own cursors (NextCursorer), which can't be the cursors of
+// each edge.
+var ErrConnectionNotSupported = errors.New("store does not support connections")
+
+// -----------------------------------------------------------------------------
+// Relay connection
+// -----------------------------------------------------------------------------
+
+// ConnectionArgs are the Relay connection arguments.
+type ConnectionArgs struct {
+	First  *int64
+	After  *string
+	Last   *int64
+	Before *string
+}
+
+// Connection is a Relay connection.
+type Connection struct {
+	Edges    []Edge   `json:"edges"`
+	PageInfo PageInfo `json:"pageInfo"`
+}
+
+// Edge is an item of a Relay connection.
+type Edge struct {
+	Cursor string      `json:"cursor"`
+	Node   interface{} `json:"node"`
+}
+
+// PageInfo is the page information of a Relay connection.
+type PageInfo struct {
+	HasNextPage     bool        `json:"hasNextPage"`
+	HasPreviousPage bool        `json:"hasPreviousPage"`
+	StartCursor     null.String `json:"startCursor"`
+	EndCursor       null.String `json:"endCursor"`
+}
+
+// ConnectionPaginator is a CursorPaginator driven by Relay connection
+// arguments.
+type ConnectionPaginator struct {
+	*CursorPaginator
+	args ConnectionArgs
+}
+
+// NewConnectionPaginator returns a new ConnectionPaginator instance.
+//
+// first and after paginate forward like the cursor paginator, last and
+// before paginate backward and need a store implementing Seeker. Stores
+// implementing NextCursorer aren't supported.
+func NewConnectionPaginator(store Store, args ConnectionArgs, options *Options) (*ConnectionPaginator, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+
+	if (args.First != nil && (args.Last != nil || args.Before != nil)) || (args.Last != nil && args.After != nil) {
+		return nil, ErrInvalidConnectionArgs
+	}
+
+	if _, ok := store.(NextCursorer); ok {
+		return nil, ErrConnectionNotSupported
+	}
+
+	paginator, err := newCursorPaginator(store, options)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ConnectionPaginator{
+		CursorPaginator: paginator,
+		args:            args,
+	}
+
+	var (
+		limit  = args.First
+		cursor = args.After
+	)
+
+	if args.Last != nil || args.Before != nil {
+		if _, ok := store.(Seeker); !ok || options.CursorOptions.Nulls != "" {
+			return nil, ErrSeekNotSupported
+		}
+		limit, cursor = args.Last, args.Before
+	}
+
+	if limit != nil {
+		if err := p.setLimit(*limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if cursor != nil {
+		if err := p.setOpaqueCursor(*cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.backward() {
+		// items before the cursor, then an empty page from it to know if
+		// there is a next page
+		p.seek = &seek{before: p.Limit}
+		p.Limit = 0
+	}
+
+	return p, nil
+}
+
+// backward returns true when paginating with last and before.
+func (p *ConnectionPaginator) backward() bool {
+	return p.args.Last != nil || p.args.Before != nil
+}
+
+// Connection returns the Relay connection of the current page, or
+// ErrInvalidItems before Page.
+func (p *ConnectionPaginator) Connection() (*Connection, error) {
+	items, err := sliceValue(p.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		options = p.Options.CursorOptions
+		conn    = &Connection{Edges: []Edge{}}
+	)
+
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+		cursor, err := encodeOpaqueCursor(options, item)
+		if err != nil {
+			return nil, err
+		}
+		conn.Edges = append(conn.Edges, Edge{
+			Cursor: cursor,
+			Node:   item.Interface(),
+		})
+	}
+
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = null.StringFrom(conn.Edges[0].Cursor)
+		conn.PageInfo.EndCursor = null.StringFrom(conn.Edges[len(conn.Edges)-1].Cursor)
+	}
+
+	if p.backward() {
+		conn.PageInfo.HasPreviousPage = p.HasBefore()
+		// without before, the page ends with the last item
+		conn.PageInfo.HasNextPage = p.args.Before != nil && p.HasNext()
+	} else {
+		conn.PageInfo.HasNextPage = p.HasNext()
+	}
+
+	return conn, nil
+}
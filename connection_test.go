@@ -0,0 +1,187 @@
+package paging
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func connection(t *testing.T, p *ConnectionPaginator) *Connection {
+	conn, err := p.Connection()
+	assert.New(t).NoError(err)
+	return conn
+}
+
+func connectionIDs(conn *Connection) []int {
+	ids := make([]int, len(conn.Edges))
+	for i := range conn.Edges {
+		ids[i] = conn.Edges[i].Node.(User).ID
+	}
+	return ids
+}
+
+func TestConnectionPaginator_Forward(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	first := int64(3)
+	p, err := NewConnectionPaginator(store, ConnectionArgs{First: &first}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+
+	conn := connection(t, p)
+	is.Equal([]int{1, 2, 3}, connectionIDs(conn))
+	is.True(conn.PageInfo.HasNextPage)
+	is.False(conn.PageInfo.HasPreviousPage)
+	is.Equal(conn.Edges[0].Cursor, conn.PageInfo.StartCursor.String)
+	is.Equal(conn.Edges[2].Cursor, conn.PageInfo.EndCursor.String)
+
+	after := conn.Edges[1].Cursor
+	p, err = NewConnectionPaginator(store, ConnectionArgs{First: &first, After: &after}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+	is.Equal([]int{3, 4, 5}, connectionIDs(connection(t, p)))
+
+	after = conn.Edges[0].Cursor
+	first = 200
+	p, err = NewConnectionPaginator(store, ConnectionArgs{First: &first, After: &after}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+	conn = connection(t, p)
+	is.Len(conn.Edges, 99)
+	is.False(conn.PageInfo.HasNextPage)
+}
+
+func TestConnectionPaginator_Backward(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	last := int64(3)
+	p, err := NewConnectionPaginator(store, ConnectionArgs{Last: &last}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+
+	conn := connection(t, p)
+	is.Equal([]int{98, 99, 100}, connectionIDs(conn))
+	is.False(conn.PageInfo.HasNextPage)
+	is.True(conn.PageInfo.HasPreviousPage)
+
+	before := conn.PageInfo.StartCursor.String
+	p, err = NewConnectionPaginator(store, ConnectionArgs{Last: &last, Before: &before}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+
+	conn = connection(t, p)
+	is.Equal([]int{95, 96, 97}, connectionIDs(conn))
+	is.True(conn.PageInfo.HasNextPage)
+	is.True(conn.PageInfo.HasPreviousPage)
+
+	before = conn.Edges[1].Cursor
+	p, err = NewConnectionPaginator(store, ConnectionArgs{Before: &before}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+
+	// default limit
+	conn = connection(t, p)
+	is.Len(conn.Edges, 20)
+	is.Equal(76, conn.Edges[0].Node.(User).ID)
+	is.True(conn.PageInfo.HasPreviousPage)
+
+	last = 100
+	p, err = NewConnectionPaginator(store, ConnectionArgs{Last: &last, Before: &before}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+
+	conn = connection(t, p)
+	is.Len(conn.Edges, 95)
+	is.Equal(1, conn.Edges[0].Node.(User).ID)
+	is.False(conn.PageInfo.HasPreviousPage)
+}
+
+func TestConnectionPaginator_DateMode(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	options := NewOptions()
+	options.CursorOptions.Mode = DateModeCursor
+	options.CursorOptions.DBName = "date_creation"
+	options.CursorOptions.StructName = "DateCreation"
+	options.CursorOptions.Reverse = true
+
+	first := int64(2)
+	p, err := NewConnectionPaginator(store, ConnectionArgs{First: &first}, options)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+	is.Equal([]int{100, 99}, connectionIDs(connection(t, p)))
+
+	after := connection(t, p).PageInfo.EndCursor.String
+	p, err = NewConnectionPaginator(store, ConnectionArgs{First: &first, After: &after}, options)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+	is.Equal([]int{98, 97}, connectionIDs(connection(t, p)))
+}
+
+func TestNewConnectionPaginator_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	var (
+		one     = int64(1)
+		minus   = int64(-1)
+		invalid = "!"
+	)
+
+	_, err := NewConnectionPaginator(nil, ConnectionArgs{First: &one, Last: &one}, nil)
+	is.Equal(ErrInvalidConnectionArgs, err)
+
+	_, err = NewConnectionPaginator(nil, ConnectionArgs{First: &minus}, nil)
+	is.Equal(ErrInvalidLimitOrOffset, err)
+
+	_, err = NewConnectionPaginator(nil, ConnectionArgs{After: &invalid}, nil)
+	is.Equal(ErrInvalidCursor, err)
+
+	_, err = NewConnectionPaginator(nil, ConnectionArgs{Last: &one}, nil)
+	is.Equal(ErrSeekNotSupported, err)
+
+	_, err = NewConnectionPaginator(nextCursorStore{}, ConnectionArgs{First: &one}, nil)
+	is.Equal(ErrConnectionNotSupported, err)
+
+	// the connection needs a page
+	rebuildDB()
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+	p, err := NewConnectionPaginator(store, ConnectionArgs{First: &one}, nil)
+	is.NoError(err)
+	_, err = p.Connection()
+	is.True(errors.Is(err, ErrInvalidItems))
+}
+
+// nextCursorStore is a store making its own cursors.
+type nextCursorStore struct{}
+
+func (nextCursorStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	return nil
+}
+
+func (nextCursorStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	return nil
+}
+
+func (nextCursorStore) PaginateNextCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool, nextCursor *interface{}) error {
+	return nil
+}
@@ -7,9 +7,78 @@ const (
 	CursorType = "cursor"
 )
 
-// cursor mode, date or id
+// cursor mode, date, id, string, uuid or ulid
 const (
 	DateModeCursor = "dateCursor"
 
 	IDModeCursor = "idCursor"
+
+	StringModeCursor = "stringCursor"
+
+	UUIDModeCursor = "uuidCursor"
+
+	ULIDModeCursor = "ulidCursor"
+)
+
+// sort direction, ascending or descending
+const (
+	SortAsc = "asc"
+
+	SortDesc = "desc"
+)
+
+// nulls ordering, first or last
+const (
+	NullsFirst = "first"
+
+	NullsLast = "last"
+)
+
+// filter operators
+const (
+	FilterEqual = "eq"
+
+	FilterNotEqual = "ne"
+
+	FilterLessThan = "lt"
+
+	FilterLessThanOrEqual = "lte"
+
+	FilterGreaterThan = "gt"
+
+	FilterGreaterThanOrEqual = "gte"
+
+	FilterIn = "in"
+
+	FilterLike = "like"
+)
+
+// filter value types
+const (
+	FilterTypeString = "string"
+
+	FilterTypeInt = "int"
+
+	FilterTypeFloat = "float"
+
+	FilterTypeBool = "bool"
+
+	FilterTypeTime = "time"
+)
+
+// hook operations, paginator methods and store calls
+const (
+	OperationPage = "page"
+
+	OperationNext = "next"
+
+	OperationPrevious = "previous"
+
+	OperationPaginateOffset = "paginate_offset"
+
+	OperationCount = "count"
+
+	OperationPaginateCursor = "paginate_cursor"
+
+	OperationPaginateSeek = "paginate_seek"
 )
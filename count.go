@@ -0,0 +1,277 @@
+package paging
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCountNotSupported is returned by NewCachedCountStore when the store
+// doesn't implement Counter.
+var ErrCountNotSupported = errors.New("store does not support count")
+
+// -----------------------------------------------------------------------------
+// Count cache
+// -----------------------------------------------------------------------------
+
+// CountCache memoizes counts by query fingerprint, safe for concurrent use.
+//
+// Only the first count of a fingerprint waits for the store, concurrent
+// callers share it. Past the TTL, the memoized count is still returned while
+// a single background count refreshes it. Counts unused for a TTL past their
+// expiration are removed, and the least recently used ones are evicted
+// beyond the cache size.
+type CountCache struct {
+	// Logger logs the failed background refreshes (none when nil)
+	Logger *slog.Logger
+
+	ttl    time.Duration
+	size   int
+	mu     sync.Mutex
+	counts map[string]*list.Element
+	lru    *list.List
+	now    func() time.Time
+}
+
+// countEntry is a memoized count, done is closed once the first count is
+// known.
+type countEntry struct {
+	key        string
+	done       chan struct{}
+	count      int64
+	err        error
+	expires    time.Time
+	used       time.Time
+	refreshing bool
+}
+
+// NewCountCache returns a new CountCache instance refreshing counts older
+// than ttl, holding up to size counts (without limit when size isn't
+// positive).
+func NewCountCache(ttl time.Duration, size int) *CountCache {
+	return &CountCache{
+		ttl:    ttl,
+		size:   size,
+		counts: map[string]*list.Element{},
+		lru:    list.New(),
+		now:    time.Now,
+	}
+}
+
+// Count returns the count of the fingerprint key, counted with count when
+// it isn't memoized.
+func (c *CountCache) Count(key string, count func(count *int64) error) (int64, error) {
+	c.mu.Lock()
+	c.purge()
+
+	elem, ok := c.counts[key]
+	if !ok {
+		entry := &countEntry{key: key, done: make(chan struct{}), used: c.now()}
+		c.counts[key] = c.lru.PushFront(entry)
+		for c.size > 0 && c.lru.Len() > c.size {
+			c.remove(c.lru.Back())
+		}
+		c.mu.Unlock()
+
+		return c.fill(entry, count)
+	}
+
+	entry := elem.Value.(*countEntry)
+	entry.used = c.now()
+	c.lru.MoveToFront(elem)
+	c.mu.Unlock()
+
+	<-entry.done
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry.err != nil {
+		return 0, entry.err
+	}
+
+	if !c.now().Before(entry.expires) && !entry.refreshing {
+		entry.refreshing = true
+		go c.refresh(entry, count)
+	}
+
+	return entry.count, nil
+}
+
+// Invalidate forgets the count of the fingerprint key.
+func (c *CountCache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.counts[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// Len returns the number of memoized counts.
+func (c *CountCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// fill runs the first count of an entry, a failed count is forgotten so
+// that the next caller retries it.
+func (c *CountCache) fill(entry *countEntry, count func(count *int64) error) (int64, error) {
+	var n int64
+	err := count(&n)
+
+	c.mu.Lock()
+	entry.count, entry.err = n, err
+	entry.expires = c.now().Add(c.ttl)
+	if elem, ok := c.counts[entry.key]; err != nil && ok && elem.Value == entry {
+		c.remove(elem)
+	}
+	c.mu.Unlock()
+
+	close(entry.done)
+	return n, err
+}
+
+// refresh counts an expired entry again, it keeps its count when the count
+// fails.
+func (c *CountCache) refresh(entry *countEntry, count func(count *int64) error) {
+	var n int64
+	err := count(&n)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refreshing = false
+	if err != nil {
+		if c.Logger != nil {
+			c.Logger.Warn("paging: count refresh failed, the stale count is kept", "key", entry.key, "error", err)
+		}
+		return
+	}
+
+	entry.count = n
+	entry.expires = c.now().Add(c.ttl)
+}
+
+// purge removes the counts unused for a TTL past their expiration, from the
+// least recently used one.
+func (c *CountCache) purge() {
+	now := c.now()
+
+	for elem := c.lru.Back(); elem != nil; elem = c.lru.Back() {
+		entry := elem.Value.(*countEntry)
+		if entry.expires.IsZero() || entry.refreshing || now.Sub(entry.used) < c.ttl || now.Before(entry.expires.Add(c.ttl)) {
+			return
+		}
+		c.remove(elem)
+	}
+}
+
+// remove removes a count.
+func (c *CountCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*countEntry)
+	delete(c.counts, entry.key)
+}
+
+// -----------------------------------------------------------------------------
+// Cached count Store
+// -----------------------------------------------------------------------------
+
+// CachedCountStore is a store paginating items from another store, with its
+// counts memoized in a CountCache: items are always fresh, counts may be
+// stale by up to the cache TTL. The cached count store doesn't support Seek.
+type CachedCountStore struct {
+	store Store
+	cache *CountCache
+	key   string
+}
+
+// NewCachedCountStore returns a new cached count store instance. key is the
+// fingerprint of the query of store, which must implement Counter.
+func NewCachedCountStore(store Store, cache *CountCache, key string) (*CachedCountStore, error) {
+	if _, ok := store.(Counter); !ok {
+		return nil, ErrCountNotSupported
+	}
+
+	return &CachedCountStore{
+		store: store,
+		cache: cache,
+		key:   key,
+	}, nil
+}
+
+// Filter returns a new store with the filtered store, its fingerprint
+// includes the filters.
+func (s *CachedCountStore) Filter(filters Filters) (Store, error) {
+	store, err := ApplyFilters(s.store, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(store, s.key+"|filter:"+string(identity))
+}
+
+// Sort returns a new store with the sorted store, the order doesn't change
+// the count.
+func (s *CachedCountStore) Sort(fieldName string, reverse bool) (Store, error) {
+	sorter, ok := s.store.(Sorter)
+	if !ok {
+		return nil, ErrSortNotSupported
+	}
+
+	store, err := sorter.Sort(fieldName, reverse)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(store, s.key)
+}
+
+// wrap returns a copy of the store with store and the fingerprint key.
+func (s *CachedCountStore) wrap(store Store, key string) (Store, error) {
+	if _, ok := store.(Counter); !ok {
+		return nil, fmt.Errorf("%w: %T", ErrCountNotSupported, store)
+	}
+
+	c := *s
+	c.store, c.key = store, key
+	return &c, nil
+}
+
+// PaginateOffset paginates items from the store, with the memoized count.
+func (s *CachedCountStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	counter := s.store.(Counter)
+
+	if err := counter.PaginateOffsetItems(items, limit, offset); err != nil {
+		return err
+	}
+
+	// the count runs in the background with its own items
+	t := newItems(items)
+
+	n, err := s.cache.Count(s.key, func(count *int64) error {
+		return counter.Count(t, count)
+	})
+	if err != nil {
+		return err
+	}
+
+	*count = n
+	return nil
+}
+
+// PaginateCursor paginates items from the store.
+func (s *CachedCountStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	return s.store.PaginateCursor(items, limit, cursor, fieldName, reverse, hasnext)
+}
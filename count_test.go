@@ -0,0 +1,195 @@
+package paging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingStore is a GORM store counting its counts.
+type countingStore struct {
+	*GORMStore
+	counts int32
+}
+
+func (s *countingStore) Count(items interface{}, count *int64) error {
+	atomic.AddInt32(&s.counts, 1)
+	time.Sleep(10 * time.Millisecond)
+	return s.GORMStore.Count(items, count)
+}
+
+func TestCachedCountStore(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	gormStore, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+	counting := &countingStore{GORMStore: gormStore}
+
+	now := time.Now()
+	cache := NewCountCache(time.Minute, 0)
+	cache.now = func() time.Time { return now }
+
+	store, err := NewCachedCountStore(counting, cache, "users")
+	is.NoError(err)
+
+	// concurrent first pages share a single count
+	var wg sync.WaitGroup
+	for i := int64(0); i < 10; i++ {
+		wg.Add(1)
+		go func(offset int64) {
+			defer wg.Done()
+
+			users := []User{}
+			p, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 5, Offset: offset}, nil)
+			is.NoError(err)
+			is.NoError(p.Page(&users))
+			is.Equal(int64(100), p.Count)
+			if is.Len(users, 5) {
+				is.Equal(int(offset)+1, users[0].ID)
+			}
+		}(i * 5)
+	}
+	wg.Wait()
+	is.Equal(int32(1), atomic.LoadInt32(&counting.counts))
+
+	// items are fresh, the count is memoized
+	is.NoError(db.Delete(&User{ID: 1}).Error)
+	users := []User{}
+	p, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 5}, nil)
+	is.NoError(err)
+	is.NoError(p.Page(&users))
+	is.Equal(2, users[0].ID)
+	is.Equal(int64(100), p.Count)
+
+	// an expired count is returned while it's refreshed in the background
+	now = now.Add(time.Minute)
+	is.NoError(p.Page(&users))
+	is.Equal(int64(100), p.Count)
+	is.Eventually(func() bool {
+		count, err := cache.Count("users", nil)
+		return err == nil && count == 99
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(int32(2), atomic.LoadInt32(&counting.counts))
+
+	// filters change the fingerprint, the order doesn't
+	options := NewOptions()
+	options.SortFields = []string{"id"}
+
+	filtered, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{
+		Limit:   5,
+		Sort:    "id",
+		Filters: Filters{{DBName: "number", Operator: FilterLessThanOrEqual, Value: 50}},
+	}, options)
+	is.NoError(err)
+	is.NoError(filtered.Page(&users))
+	is.Equal(int64(49), filtered.Count)
+
+	sorted, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 5, Sort: "id", Direction: SortDesc}, options)
+	is.NoError(err)
+	is.NoError(sorted.Page(&users))
+	is.Equal(100, users[0].ID)
+	is.Equal(int64(99), sorted.Count)
+	is.Equal(int32(2), atomic.LoadInt32(&counting.counts))
+
+	cached, err := NewCachedStore(gormStore, NewLRUCache(1), "users", 0)
+	is.NoError(err)
+	_, err = NewCachedCountStore(cached, cache, "cached")
+	is.Equal(ErrCountNotSupported, err)
+}
+
+func TestCountCache_Error(t *testing.T) {
+	is := assert.New(t)
+
+	cache := NewCountCache(time.Minute, 0)
+	errCount := errors.New("count failed")
+
+	_, err := cache.Count("key", func(count *int64) error { return errCount })
+	is.Equal(errCount, err)
+
+	// failed counts aren't memoized
+	count, err := cache.Count("key", func(count *int64) error {
+		*count = 42
+		return nil
+	})
+	is.NoError(err)
+	is.Equal(int64(42), count)
+
+	cache.Invalidate("key")
+	count, err = cache.Count("key", func(count *int64) error {
+		*count = 7
+		return nil
+	})
+	is.NoError(err)
+	is.Equal(int64(7), count)
+}
+
+func TestCountCache_Size(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Now()
+	cache := NewCountCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	counts := 0
+	count := func(count *int64) error {
+		counts++
+		*count = int64(counts)
+		return nil
+	}
+
+	for _, key := range []string{"a", "b", "a", "c"} {
+		_, err := cache.Count(key, count)
+		is.NoError(err)
+	}
+	is.Equal(2, cache.Len())
+	is.Equal(3, counts)
+
+	// b is the least recently used
+	n, err := cache.Count("b", count)
+	is.NoError(err)
+	is.Equal(int64(4), n)
+
+	// counts unused for a TTL past their expiration are removed
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Count("d", count)
+	is.NoError(err)
+	is.Equal(1, cache.Len())
+}
+
+func TestCountCache_RefreshError(t *testing.T) {
+	is := assert.New(t)
+
+	buf := &bytes.Buffer{}
+
+	now := time.Now()
+	cache := NewCountCache(time.Minute, 0)
+	cache.Logger = slog.New(slog.NewTextHandler(buf, nil))
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Count("key", func(count *int64) error {
+		*count = 42
+		return nil
+	})
+	is.NoError(err)
+
+	// the stale count is kept and the error logged
+	now = now.Add(time.Minute)
+	count, err := cache.Count("key", func(count *int64) error { return errors.New("count failed") })
+	is.NoError(err)
+	is.Equal(int64(42), count)
+
+	is.Eventually(func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return strings.Contains(buf.String(), `level=WARN msg="paging: count refresh failed, the stale count is kept" key=key error="count failed"`)
+	}, time.Second, 5*time.Millisecond)
+}
@@ -10,6 +10,12 @@ const (
 	// DefaultOffsetKeyName is the request offset key name.
 	DefaultOffsetKeyName = "offset"
 
+	// DefaultSortKeyName is the request sort key name.
+	DefaultSortKeyName = "sort"
+
+	// DefaultDirectionKeyName is the request sort direction key name.
+	DefaultDirectionKeyName = "direction"
+
 	// DefaultCursorKeyName is the request cursor key name.
 	DefaultCursorKeyName = "since"
 
@@ -18,4 +24,11 @@ const (
 
 	// DefaultCursorStructName is the default cursor struct field name
 	DefaultCursorStructName = "ID"
+
+	// CursorTag is the struct tag marking the cursor field of items:
+	// `paging:"cursor"`
+	CursorTag = "cursor"
+
+	// NullCursorPrefix prefixes the key of a cursor in rows with a NULL value
+	NullCursorPrefix = "null:"
 )
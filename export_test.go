@@ -0,0 +1,16 @@
+package paging
+
+import "github.com/jinzhu/gorm"
+
+// RebuildDB rebuilds the test database and returns it, for the external
+// tests.
+func RebuildDB() *gorm.DB {
+	rebuildDB()
+	return db
+}
+
+// SetLimit sets the limit of p, clamped to the maximum limit, for the
+// external tests.
+func (p *CursorPaginator) SetLimit(limit int64) error {
+	return p.setLimit(limit)
+}
@@ -0,0 +1,243 @@
+package paging
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFilter is returned when a filter from the request can't be parsed.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ErrFilterNotSupported is returned when filters are applied to a store
+// which doesn't support them.
+var ErrFilterNotSupported = errors.New("store does not support filters")
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// Filterer is a store which can be filtered.
+type Filterer interface {
+	// Filter returns a new store restricted to items matching all filters.
+	Filter(filters Filters) (Store, error)
+}
+
+// -----------------------------------------------------------------------------
+// Filter spec
+// -----------------------------------------------------------------------------
+
+// FilterSpec declares the filters allowed on a paginated query.
+//
+// Filters are read from the query string as "name=value", using the field's
+// default operator, or "name[op]=value", e.g. "?status=active&age[gte]=18".
+type FilterSpec struct {
+	// Fields are the allowed fields
+	Fields []FilterField
+}
+
+// FilterField is a field which can be filtered.
+type FilterField struct {
+	// Name is the query string key name
+	Name string
+	// DBName is the database column name (defaults to Name)
+	DBName string
+	// Type is used to coerce query string values (defaults to FilterTypeString)
+	Type string
+	// Operators are the allowed operators, the first one is used when
+	// none is given (defaults to FilterEqual)
+	Operators []string
+}
+
+// NewFilterSpec returns a new filter spec with the given fields.
+func NewFilterSpec(fields ...FilterField) *FilterSpec {
+	return &FilterSpec{Fields: fields}
+}
+
+// Parse returns the filters found in the request.
+func (s *FilterSpec) Parse(request *http.Request) (Filters, error) {
+	return s.ParseValues(request.URL.Query())
+}
+
+// ParseValues returns the filters found in the given query string values.
+// Keys which don't match any field are ignored.
+func (s *FilterSpec) ParseValues(values url.Values) (Filters, error) {
+	var filters Filters
+
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		for _, operator := range field.operators() {
+			for _, key := range field.keys(operator) {
+				for _, raw := range values[key] {
+					filter, err := field.parse(key, operator, raw)
+					if err != nil {
+						return nil, err
+					}
+					filters = append(filters, filter)
+				}
+			}
+		}
+	}
+
+	return filters, nil
+}
+
+func (f *FilterField) operators() []string {
+	if len(f.Operators) == 0 {
+		return []string{FilterEqual}
+	}
+	return f.Operators
+}
+
+func (f *FilterField) dbName() string {
+	if f.DBName == "" {
+		return f.Name
+	}
+	return f.DBName
+}
+
+// keys returns the query string keys for the given operator.
+func (f *FilterField) keys(operator string) []string {
+	keys := []string{fmt.Sprintf("%s[%s]", f.Name, operator)}
+	if operator == f.operators()[0] {
+		keys = append(keys, f.Name)
+	}
+	return keys
+}
+
+func (f *FilterField) parse(key, operator, raw string) (Filter, error) {
+	filter := Filter{
+		Key:      key,
+		Name:     f.Name,
+		DBName:   f.dbName(),
+		Operator: operator,
+		raw:      raw,
+	}
+
+	if operator == FilterIn {
+		var values []interface{}
+		for _, part := range strings.Split(raw, ",") {
+			value, err := coerceFilterValue(f.Type, part)
+			if err != nil {
+				return Filter{}, fmt.Errorf("%w %q: %v", ErrInvalidFilter, key, err)
+			}
+			values = append(values, value)
+		}
+		filter.Value = values
+		return filter, nil
+	}
+
+	value, err := coerceFilterValue(f.Type, raw)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w %q: %v", ErrInvalidFilter, key, err)
+	}
+
+	if operator == FilterLike {
+		value = "%" + likeEscaper.Replace(fmt.Sprint(value)) + "%"
+	}
+
+	filter.Value = value
+	return filter, nil
+}
+
+// likeEscaper escapes the LIKE wildcards, matched with ESCAPE '!': a
+// backslash would escape the closing quote on MySQL.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// likeUnescaper reverts likeEscaper.
+var likeUnescaper = strings.NewReplacer("!!", "!", "!%", "%", "!_", "_")
+
+// coerceFilterValue converts a query string value to the given type.
+func coerceFilterValue(typ string, raw string) (interface{}, error) {
+	switch typ {
+	case "", FilterTypeString:
+		return raw, nil
+	case FilterTypeInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case FilterTypeFloat:
+		return strconv.ParseFloat(raw, 64)
+	case FilterTypeBool:
+		return strconv.ParseBool(raw)
+	case FilterTypeTime:
+		return parseTime(raw)
+	}
+	return nil, fmt.Errorf("unknown filter type %q", typ)
+}
+
+// -----------------------------------------------------------------------------
+// Filters
+// -----------------------------------------------------------------------------
+
+// Filter is a condition parsed from the request.
+type Filter struct {
+	// Key is the query string key
+	Key string
+	// Name is the field name
+	Name string
+	// DBName is the database column name
+	DBName string
+	// Operator is the filter operator
+	Operator string
+	// Value is the coerced value, a []interface{} for FilterIn
+	Value interface{}
+
+	raw string
+}
+
+// SQL returns the SQL condition and its arguments.
+func (f Filter) SQL() (string, []interface{}) {
+	switch f.Operator {
+	case FilterNotEqual:
+		return fmt.Sprintf("%s <> ?", f.DBName), []interface{}{f.Value}
+	case FilterLessThan:
+		return fmt.Sprintf("%s < ?", f.DBName), []interface{}{f.Value}
+	case FilterLessThanOrEqual:
+		return fmt.Sprintf("%s <= ?", f.DBName), []interface{}{f.Value}
+	case FilterGreaterThan:
+		return fmt.Sprintf("%s > ?", f.DBName), []interface{}{f.Value}
+	case FilterGreaterThanOrEqual:
+		return fmt.Sprintf("%s >= ?", f.DBName), []interface{}{f.Value}
+	case FilterIn:
+		return fmt.Sprintf("%s IN (?)", f.DBName), []interface{}{f.Value}
+	case FilterLike:
+		return fmt.Sprintf("%s LIKE ? ESCAPE '!'", f.DBName), []interface{}{f.Value}
+	}
+	return fmt.Sprintf("%s = ?", f.DBName), []interface{}{f.Value}
+}
+
+// Contains returns the substring matched by a FilterLike filter, without its
+// wildcards and escapes.
+func (f Filter) Contains() string {
+	pattern, _ := f.Value.(string)
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "%"), "%")
+	return likeUnescaper.Replace(pattern)
+}
+
+// Filters is a list of filters.
+type Filters []Filter
+
+// Values returns filters as query string values.
+func (f Filters) Values() url.Values {
+	values := url.Values{}
+	for _, filter := range f {
+		values.Add(filter.Key, filter.raw)
+	}
+	return values
+}
+
+// ApplyFilters returns the store restricted to items matching filters.
+func ApplyFilters(store Store, filters Filters) (Store, error) {
+	if len(filters) == 0 {
+		return store, nil
+	}
+
+	filterer, ok := store.(Filterer)
+	if !ok {
+		return nil, ErrFilterNotSupported
+	}
+
+	return filterer.Filter(filters)
+}
@@ -0,0 +1,118 @@
+package paging
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSpec_ParseValues(t *testing.T) {
+	is := assert.New(t)
+
+	spec := NewFilterSpec(
+		FilterField{Name: "name"},
+		FilterField{Name: "number", Type: FilterTypeInt, Operators: []string{FilterEqual, FilterGreaterThan, FilterIn}},
+		FilterField{Name: "created_after", DBName: "date_creation", Type: FilterTypeTime, Operators: []string{FilterGreaterThan}},
+	)
+
+	filters, err := spec.ParseValues(url.Values{
+		"name":          []string{"user-1"},
+		"number[gt]":    []string{"10"},
+		"number[in]":    []string{"1,2,3"},
+		"created_after": []string{"2017-01-17"},
+		"limit":         []string{"10"},
+		"name[like]":    []string{"user"}, // operator not allowed, ignored
+	})
+	is.NoError(err)
+	is.Len(filters, 4)
+
+	is.Equal(Filter{Key: "name", Name: "name", DBName: "name", Operator: FilterEqual, Value: "user-1", raw: "user-1"}, filters[0])
+	is.Equal(int64(10), filters[1].Value)
+	is.Equal(FilterGreaterThan, filters[1].Operator)
+	is.Equal([]interface{}{int64(1), int64(2), int64(3)}, filters[2].Value)
+	is.Equal("date_creation", filters[3].DBName)
+	is.Equal(time.Date(2017, 1, 17, 0, 0, 0, 0, time.UTC), filters[3].Value)
+
+	query, args := filters[1].SQL()
+	is.Equal("number > ?", query)
+	is.Equal([]interface{}{int64(10)}, args)
+
+	_, err = spec.ParseValues(url.Values{"number": []string{"ten"}})
+	is.True(errors.Is(err, ErrInvalidFilter))
+}
+
+func TestFilters_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	options := NewOptions()
+	options.FilterSpec = NewFilterSpec(
+		FilterField{Name: "number", Type: FilterTypeInt, Operators: []string{FilterLessThanOrEqual}},
+		FilterField{Name: "name", Operators: []string{FilterLike}},
+	)
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=5&number=50&name=user-1", nil)
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}).Order("number"))
+	is.NoError(err)
+
+	paginator, err := NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	// user-1, user-10..19
+	is.Equal(int64(11), paginator.Count)
+	is.Len(users, 5)
+	is.Equal(1, users[0].Number)
+	is.Equal("?limit=5&offset=5&name=user-1&number=50", paginator.NextURI.String)
+
+	request, _ = http.NewRequest("GET", "http://example.com?number=a", nil)
+	_, err = NewOffsetPaginator(store, request, options)
+	is.True(errors.Is(err, ErrInvalidFilter))
+}
+
+func TestFilters_Like(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	spec := NewFilterSpec(FilterField{Name: "name", Operators: []string{FilterLike}})
+
+	filters, err := spec.ParseValues(url.Values{"name": []string{`user_1%!\`}})
+	is.NoError(err)
+	is.Equal(`%user!_1!%!!\%`, filters[0].Value)
+	is.Equal(`user_1%!\`, filters[0].Contains())
+
+	query, _ := filters[0].SQL()
+	is.Equal(`name LIKE ? ESCAPE '!'`, query)
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	// "_" is matched literally, not as any character
+	filters, _ = spec.ParseValues(url.Values{"name": []string{"user_1"}})
+	filtered, err := ApplyFilters(store, filters)
+	is.NoError(err)
+	is.NoError(filtered.PaginateOffset(&users, 10, 0, new(int64)))
+	is.Len(users, 0)
+
+	filters, _ = spec.ParseValues(url.Values{"name": []string{"user-1"}})
+	filtered, err = ApplyFilters(store, filters)
+	is.NoError(err)
+	is.NoError(filtered.PaginateOffset(&users, 100, 0, new(int64)))
+	is.Len(users, 12) // user-1, user-10..19, user-100
+}
+
+func TestApplyFilters_NotSupported(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := ApplyFilters(nil, Filters{{Key: "name"}})
+	is.Equal(ErrFilterNotSupported, err)
+}
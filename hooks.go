@@ -0,0 +1,103 @@
+package paging
+
+import (
+	"context"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Hooks
+// -----------------------------------------------------------------------------
+
+// Hook observes paginators, set in Options.Hooks.
+//
+// Hooks are called around Page, Next and Previous, and around each store
+// call nested in them. Before returns the context given to After and to the
+// nested operations, so that tracing spans can be nested.
+type Hook interface {
+	// Before is called before an operation.
+	Before(ctx context.Context, event *HookEvent) context.Context
+	// After is called after an operation, with its duration, item count and
+	// error.
+	After(ctx context.Context, event *HookEvent)
+}
+
+// HookEvent is a paginator operation or a store call.
+type HookEvent struct {
+	// Operation is the paginator method (OperationPage, OperationNext or
+	// OperationPrevious) or the store method called
+	Operation string
+	// Type is the pagination type, OffsetType or CursorType
+	Type string
+	// Limit is the number of items requested
+	Limit int64
+	// LimitClamped is true when the requested limit was restricted to
+	// Options.MaxLimit
+	LimitClamped bool
+	// Offset is the offset of offset pagination
+	Offset int64
+	// Cursor is the cursor of cursor pagination, or of an offset keyset page
+	Cursor interface{}
+
+	// Items is the number of items paginated
+	Items int
+	// Count is the count of offset pagination
+	Count int64
+	// Duration is the duration of the operation
+	Duration time.Duration
+	// Err is the error of the operation
+	Err error
+}
+
+// hookContext returns the context given to the hooks: Context, or the
+// request context.
+func (p *paginator) hookContext() context.Context {
+	if p.Context != nil {
+		return p.Context
+	}
+	if p.Request != nil {
+		return p.Request.Context()
+	}
+	return context.Background()
+}
+
+// contextStore returns the store running its queries with ctx, the nested
+// context of Context or of the request context, when the store is a
+// Contexter and either is set.
+func (p *paginator) contextStore(ctx context.Context) Store {
+	store, ok := p.Store.(Contexter)
+	if !ok || (p.Context == nil && p.Request == nil) {
+		return p.Store
+	}
+	return store.WithContext(ctx)
+}
+
+// observe runs the operation of event between the calls of the hooks, fn
+// is given the context of its nested operations. The number of items is
+// read from items once the operation is done.
+func (p *paginator) observe(ctx context.Context, event *HookEvent, items interface{}, fn func(ctx context.Context) error) error {
+	hooks := p.Options.Hooks
+	if len(hooks) == 0 {
+		return fn(ctx)
+	}
+
+	contexts := make([]context.Context, len(hooks))
+	for i, hook := range hooks {
+		ctx = hook.Before(ctx, event)
+		contexts[i] = ctx
+	}
+
+	start := time.Now()
+	event.Err = fn(ctx)
+	event.Duration = time.Since(start)
+
+	if items != nil && event.Err == nil {
+		event.Items, _ = getLen(items)
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].After(contexts[i], event)
+	}
+
+	return event.Err
+}
@@ -0,0 +1,206 @@
+package paging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type operationKey struct{}
+
+// recordingHook records the operations, and the operation each one is
+// nested in.
+type recordingHook struct {
+	calls  []string
+	events []HookEvent
+}
+
+func (h *recordingHook) Before(ctx context.Context, event *HookEvent) context.Context {
+	parent, _ := ctx.Value(operationKey{}).(string)
+	h.calls = append(h.calls, fmt.Sprintf("before %s in %q", event.Operation, parent))
+	return context.WithValue(ctx, operationKey{}, event.Operation)
+}
+
+func (h *recordingHook) After(ctx context.Context, event *HookEvent) {
+	h.calls = append(h.calls, fmt.Sprintf("after %s", ctx.Value(operationKey{})))
+	h.events = append(h.events, *event)
+}
+
+func TestHooks_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	hook := &recordingHook{}
+	options := NewOptions()
+	options.MaxLimit = 10
+	options.MaxOffset = 50
+	options.Hooks = []Hook{hook}
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=50&offset=40", nil)
+	request = request.WithContext(context.WithValue(request.Context(), operationKey{}, "request"))
+
+	users := []User{}
+	paginator, err := NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	// the count is observed apart, nested in the store call
+	is.Equal([]string{
+		`before page in "request"`,
+		`before paginate_offset in "page"`,
+		`before count in "paginate_offset"`,
+		`after count`,
+		`after paginate_offset`,
+		`after page`,
+	}, hook.calls)
+
+	page := hook.events[2]
+	is.Equal(OperationPage, page.Operation)
+	is.Equal(OffsetType, page.Type)
+	is.Equal(int64(10), page.Limit)
+	is.True(page.LimitClamped)
+	is.Equal(int64(40), page.Offset)
+	is.Equal(10, page.Items)
+	is.Equal(int64(100), page.Count)
+	is.NoError(page.Err)
+	is.True(page.Duration > 0)
+
+	is.Equal(OperationCount, hook.events[0].Operation)
+	is.Equal(int64(100), hook.events[0].Count)
+	is.Equal(10, hook.events[1].Items)
+	is.Equal(int64(100), hook.events[1].Count)
+
+	// Next and Previous are operations of their own
+	hook.calls, hook.events = nil, nil
+	_, err = paginator.Previous(&[]User{})
+	is.NoError(err)
+	is.Equal(`before previous in "request"`, hook.calls[0])
+	is.Equal(int64(30), hook.events[2].Offset)
+
+	hook.calls, hook.events = nil, nil
+	np, err := paginator.Next(&[]User{})
+	is.NoError(err)
+	is.False(np.HasNext())
+
+	last := hook.events[len(hook.events)-1]
+	is.Equal(OperationNext, last.Operation)
+	is.Equal(int64(50), last.Offset)
+
+	// failed operations carry their error
+	hook.calls, hook.events = nil, nil
+	paginator, err = NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10, Offset: 60}, options)
+	is.NoError(err)
+	is.Equal(ErrMaxOffsetExceeded, paginator.Page(&users))
+
+	last = hook.events[len(hook.events)-1]
+	is.Equal(OperationPage, last.Operation)
+	is.Equal(int64(60), last.Offset)
+	is.Equal(ErrMaxOffsetExceeded, last.Err)
+}
+
+// batchStore is a Counter store paginating offsets in a single batch.
+type batchStore struct {
+	store   *GORMStore
+	batches int
+}
+
+func (s *batchStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	s.batches++
+	return s.store.PaginateOffset(items, limit, offset, count)
+}
+
+func (s *batchStore) PaginateOffsetItems(items interface{}, limit, offset int64) error {
+	return s.store.PaginateOffsetItems(items, limit, offset)
+}
+
+func (s *batchStore) Count(items interface{}, count *int64) error {
+	return s.store.Count(items, count)
+}
+
+func (s *batchStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	return s.store.PaginateCursor(items, limit, cursor, fieldName, reverse, hasnext)
+}
+
+func TestHooks_PaginateOffset(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	gormStore, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+	store := &batchStore{store: gormStore}
+
+	hook := &recordingHook{}
+	options := NewOptions()
+	options.Hooks = []Hook{hook}
+
+	// hooks don't change the store calls
+	paginator, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&[]User{}))
+	is.Equal(1, store.batches)
+	is.Equal(int64(100), paginator.Count)
+	is.Equal([]string{
+		`before page in ""`,
+		`before paginate_offset in "page"`,
+		`after paginate_offset`,
+		`after page`,
+	}, hook.calls)
+}
+
+func TestHooks_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	hook := &recordingHook{}
+	options := NewOptions()
+	options.Hooks = []Hook{hook}
+
+	users := []User{}
+	paginator, err := NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10, Cursor: int64(20)}, options)
+	is.NoError(err)
+	paginator.Context = context.WithValue(context.Background(), operationKey{}, "job")
+	is.NoError(paginator.Page(&users))
+
+	np, err := paginator.Next(&[]User{})
+	is.NoError(err)
+	is.True(np.HasNext())
+
+	is.Equal([]string{
+		`before page in "job"`,
+		`before paginate_cursor in "page"`,
+		`after paginate_cursor`,
+		`after page`,
+		`before next in "job"`,
+		`before paginate_cursor in "next"`,
+		`after paginate_cursor`,
+		`after next`,
+	}, hook.calls)
+
+	is.Equal(CursorType, hook.events[3].Type)
+	is.Equal(30, hook.events[3].Cursor)
+	is.Equal(10, hook.events[3].Items)
+	is.False(hook.events[3].LimitClamped)
+
+	// seeks are observed with their limit
+	hook.calls, hook.events = nil, nil
+	is.NoError(paginator.SeekAround(50, 3))
+	is.NoError(paginator.Page(&users))
+	is.Len(hook.events, 3)
+	is.Equal(OperationPaginateSeek, hook.events[0].Operation)
+	is.Equal(int64(3), hook.events[0].Limit)
+	is.Equal(3, hook.events[0].Items)
+	is.Equal(int64(10), hook.events[1].Limit)
+	is.Equal(13, hook.events[2].Items)
+}
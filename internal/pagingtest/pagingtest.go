@@ -0,0 +1,124 @@
+// Package pagingtest provides the store fake and assertions shared by the
+// tests of the paging stores and subpackages.
+package pagingtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+)
+
+// -----------------------------------------------------------------------------
+// Store
+// -----------------------------------------------------------------------------
+
+// NumberStore is a store of the numbers 1 to 100, paginated into a *[]int.
+type NumberStore struct{}
+
+// PaginateOffset paginates numbers.
+func (NumberStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	numbers := items.(*[]int)
+	*numbers = nil
+	for i := offset + 1; i <= offset+limit && i <= 100; i++ {
+		*numbers = append(*numbers, int(i))
+	}
+	*count = 100
+	return nil
+}
+
+// PaginateCursor doesn't paginate anything.
+func (NumberStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	return nil
+}
+
+// StoreErrors asserts that store returns paging.ErrInvalidItems, without
+// panicking, when items isn't a pointer to a slice, for all its paginate
+// methods with the cursor field fieldName.
+func StoreErrors(t *testing.T, store paging.Store, fieldName string) {
+	is := assert.New(t)
+
+	var (
+		count   int64
+		hasnext bool
+	)
+
+	for _, items := range []interface{}{[]struct{}{}, (*[]struct{})(nil), &struct{}{}} {
+		is.True(errors.Is(store.PaginateOffset(items, 10, 0, &count), paging.ErrInvalidItems), "PaginateOffset(%T)", items)
+		is.True(errors.Is(store.PaginateCursor(items, 10, nil, fieldName, false, &hasnext), paging.ErrInvalidItems), "PaginateCursor(%T)", items)
+
+		if counter, ok := store.(paging.Counter); ok {
+			is.True(errors.Is(counter.PaginateOffsetItems(items, 10, 0), paging.ErrInvalidItems), "PaginateOffsetItems(%T)", items)
+		}
+		if seeker, ok := store.(paging.Seeker); ok {
+			is.True(errors.Is(seeker.PaginateSeek(items, 10, nil, fieldName, false, false, &hasnext), paging.ErrInvalidItems), "PaginateSeek(%T)", items)
+		}
+		if cursorer, ok := store.(paging.NextCursorer); ok {
+			var next interface{}
+			is.True(errors.Is(cursorer.PaginateNextCursor(items, 10, nil, fieldName, false, &hasnext, &next), paging.ErrInvalidItems), "PaginateNextCursor(%T)", items)
+		}
+	}
+}
+
+// PageItems returns the items of a page returned by Next, paginated into a
+// *[]T.
+func PageItems[T any](p paging.Paginator) []T {
+	switch p := p.(type) {
+	case *paging.CursorPaginator:
+		return *p.Items.(*[]T)
+	case *paging.OffsetPaginator:
+		return *p.Items.(*[]T)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Logging
+// -----------------------------------------------------------------------------
+
+// DebugLogger returns a logger writing debug records as text into buf,
+// without their time so that they can be asserted.
+func DebugLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// -----------------------------------------------------------------------------
+// Render
+// -----------------------------------------------------------------------------
+
+// RenderRequest returns the request of the numbers page rendered by the
+// framework handlers.
+func RenderRequest() *http.Request {
+	return httptest.NewRequest("GET", "/numbers?limit=2&offset=4", nil)
+}
+
+// AssertRender asserts the rendered response of RenderRequest: the Link
+// header and the items and meta of the body.
+func AssertRender(t *testing.T, rec *httptest.ResponseRecorder) {
+	is := assert.New(t)
+
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal(`</numbers?limit=2&offset=6>; rel="next", </numbers?limit=2&offset=2>; rel="prev"`, rec.Header().Get("Link"))
+
+	var res struct {
+		Items []int                  `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	is.Equal([]int{5, 6}, res.Items)
+	is.Equal(float64(100), res.Meta["total_count"])
+}
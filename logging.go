@@ -0,0 +1,19 @@
+package paging
+
+import (
+	"context"
+)
+
+// -----------------------------------------------------------------------------
+// Logging
+// -----------------------------------------------------------------------------
+
+// logDebug logs a pagination decision at debug level with the logger of
+// options, when set.
+func logDebug(ctx context.Context, options *Options, msg string, args ...interface{}) {
+	if options == nil || options.Logger == nil {
+		return
+	}
+
+	options.Logger.DebugContext(ctx, msg, args...)
+}
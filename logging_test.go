@@ -0,0 +1,124 @@
+package paging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+func TestLogging(t *testing.T) {
+	is := assert.New(t)
+
+	db := paging.RebuildDB()
+
+	buf := &bytes.Buffer{}
+	logger := pagingtest.DebugLogger(buf)
+
+	options := paging.NewOptions()
+	options.MaxLimit = 10
+	options.Logger = logger
+
+	// fallbacks to defaults and clamping
+	request, _ := http.NewRequest("GET", "http://example.com?limit=abc&offset=-&since=x", nil)
+	is.Equal(int64(10), paging.GetLimitFromRequest(request, options))
+	is.Equal(int64(0), paging.GetOffsetFromRequest(request, options))
+	is.Equal(int64(0), paging.GetCursorValueFromRequest(request, options))
+	is.Contains(buf.String(), `msg="paging: invalid limit, using the default limit" limit=abc default_limit=20`)
+	is.Contains(buf.String(), `msg="paging: limit clamped to the maximum limit" limit=20 max_limit=10`)
+	is.Contains(buf.String(), `msg="paging: invalid offset, using the first offset" offset=-`)
+	is.Contains(buf.String(), `msg="paging: invalid cursor, using the first item" cursor=x mode=idCursor`)
+
+	buf.Reset()
+	request, _ = http.NewRequest("GET", "http://example.com?limit=50&offset=20", nil)
+	store, err := paging.NewGORMStore(db.Model(&paging.User{}).Order("id"))
+	is.NoError(err)
+	paginator, err := paging.NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&[]paging.User{}))
+
+	is.Contains(buf.String(), `msg="paging: limit clamped to the maximum limit" limit=50 max_limit=10`)
+	is.Contains(buf.String(), `msg="paging: page request" limit=10 offset=20 cursor=0 sort="" direction="" filters=""`)
+	is.Contains(buf.String(), `msg="paging: page URIs" previous="?limit=10&offset=10" next="?limit=10&offset=30"`)
+
+	// cursor predicates are logged by the store
+	buf.Reset()
+	cursor, err := paging.NewCursorPaginatorFromPageRequest(store.WithLogger(logger), paging.PageRequest{Limit: 10, Cursor: int64(42)}, options)
+	is.NoError(err)
+	is.NoError(cursor.Page(&[]paging.User{}))
+
+	is.Contains(buf.String(), `msg="paging: cursor predicate" predicate="id > ?" args=[42] limit=10`)
+	is.Contains(buf.String(), `msg="paging: page URIs" previous="" next="?limit=10&since=52"`)
+
+	buf.Reset()
+	sqlStore, err := paging.NewSQLStore(db, "SELECT * FROM users", nil)
+	is.NoError(err)
+	var hasnext bool
+	is.NoError(sqlStore.WithLogger(logger).PaginateCursor(&[]paging.User{}, 10, nil, "id", true, &hasnext))
+	is.Contains(buf.String(), `msg="paging: cursor predicate, from the first item" limit=10`)
+
+	// nothing is logged without logger
+	buf.Reset()
+	options.Logger = nil
+	paginator, err = paging.NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&[]paging.User{}))
+	is.Empty(buf.String())
+}
+
+type requestKey struct{}
+
+// contextHandler records the request of the context of each record.
+type contextHandler struct {
+	slog.Handler
+	requests []interface{}
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.requests = append(h.requests, ctx.Value(requestKey{}))
+	return h.Handler.Handle(ctx, record)
+}
+
+func TestLogging_Context(t *testing.T) {
+	is := assert.New(t)
+
+	db := paging.RebuildDB()
+
+	handler := &contextHandler{Handler: pagingtest.DebugLogger(&bytes.Buffer{}).Handler()}
+
+	options := paging.NewOptions()
+	options.MaxLimit = 10
+	options.Logger = slog.New(handler)
+
+	// decisions are logged with the request context
+	request, _ := http.NewRequest("GET", "http://example.com?limit=50&offset=x", nil)
+	request = request.WithContext(context.WithValue(request.Context(), requestKey{}, "request"))
+	store, err := paging.NewGORMStore(db.Model(&paging.User{}).Order("id"))
+	is.NoError(err)
+	paginator, err := paging.NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&[]paging.User{}))
+
+	is.Len(handler.requests, 4)
+	for _, value := range handler.requests {
+		is.Equal("request", value)
+	}
+
+	// or with the paginator context, given to the store
+	handler.requests = nil
+	cursor, err := paging.NewCursorPaginatorFromPageRequest(store.WithLogger(options.Logger), paging.PageRequest{Limit: 5}, options)
+	is.NoError(err)
+	cursor.Context = context.WithValue(context.Background(), requestKey{}, "job")
+	is.NoError(cursor.SetLimit(50))
+	is.NoError(cursor.Page(&[]paging.User{}))
+
+	is.Len(handler.requests, 3)
+	for _, value := range handler.requests {
+		is.Equal("job", value)
+	}
+}
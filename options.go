@@ -1,5 +1,14 @@
 package paging
 
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
 // -----------------------------------------------------------------------------
 // Options
 // -----------------------------------------------------------------------------
@@ -14,13 +23,32 @@ type Options struct {
 	LimitKeyName string
 	// OffsetKeyName is the query string key name for the offset
 	OffsetKeyName string
+	// MaxOffset is the maximum offset of offset pagination, deeper offsets
+	// are rejected (no maximum when zero)
+	MaxOffset int64
+	// KeysetFallback makes offset pagination switch to keyset pagination on
+	// the cursor past MaxOffset instead of rejecting the offset
+	KeysetFallback bool
+	// SortKeyName is the query string key name for the sort field
+	SortKeyName string
+	// DirectionKeyName is the query string key name for the sort direction
+	DirectionKeyName string
+	// SortFields are the database columns allowed as sort field
+	SortFields []string
 	// CursorOptions
 	CursorOptions *CursorOptions
+	// FilterSpec declares the filters allowed in the query string
+	FilterSpec *FilterSpec
+	// Hooks observe the paginators and their store calls
+	Hooks []Hook
+	// Logger logs the pagination decisions at debug level (none when nil),
+	// stores log their predicates with their own WithLogger logger
+	Logger *slog.Logger
 }
 
 // CursorOptions group all options about cursor pagination
 type CursorOptions struct {
-	// Mode set type of cursor, an ID or a Date (time.Time)
+	// Mode set type of cursor, an ID, a Date (time.Time), a string, an UUID or an ULID
 	Mode string
 	// KeyName is the query string key name for the cursor
 	KeyName string
@@ -30,6 +58,17 @@ type CursorOptions struct {
 	StructName string
 	// Reverse turn true to work with DESC request
 	Reverse bool
+	// Nulls orders rows with a NULL cursor first or last (NullsFirst or
+	// NullsLast), they are ignored when empty
+	Nulls string
+	// KeyDBName is the unique column ordering rows with a NULL cursor
+	KeyDBName string
+	// KeyStructName is the unique struct field ordering rows with a NULL cursor
+	KeyStructName string
+	// Type is the type of the items, a struct or a pointer to a struct: the
+	// cursor field is then resolved and checked when paginators are created,
+	// instead of from the items of each page
+	Type reflect.Type
 }
 
 // NewOptions returns defaults options
@@ -38,12 +77,156 @@ func NewOptions() *Options {
 		DefaultLimit:  int64(DefaultLimit),
 		LimitKeyName:  DefaultLimitKeyName,
 		OffsetKeyName: DefaultOffsetKeyName,
+
+		SortKeyName:      DefaultSortKeyName,
+		DirectionKeyName: DefaultDirectionKeyName,
 		CursorOptions: &CursorOptions{
 			Mode:       IDModeCursor,
 			KeyName:    DefaultCursorKeyName,
 			DBName:     DefaultCursorDBName,
 			StructName: DefaultCursorStructName,
 			Reverse:    false,
+
+			KeyDBName:     DefaultCursorDBName,
+			KeyStructName: DefaultCursorStructName,
 		},
 	}
 }
+
+// declaredCursorOptions returns options with the cursor field of the
+// declared CursorOptions.Type, options when there is none.
+func declaredCursorOptions(store Store, options *Options) (*Options, error) {
+	t := options.CursorOptions.Type
+	if t == nil {
+		return options, nil
+	}
+
+	st := structType(t)
+	if st == nil {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidItems, t)
+	}
+
+	return resolveCursorOptions(store, st, options)
+}
+
+// itemCursorOptions returns options with the cursor field of items, already
+// resolved when CursorOptions.Type is declared: items must then be of that
+// type.
+func itemCursorOptions(store Store, items interface{}, options *Options) (*Options, error) {
+	if t := options.CursorOptions.Type; t != nil {
+		if itemStructType(items) != structType(t) {
+			return nil, fmt.Errorf("%w: items are not of type %s", ErrInvalidItems, t)
+		}
+		return options, nil
+	}
+
+	return resolveCursorOptions(store, itemStructType(items), options)
+}
+
+// resolveCursorOptions returns options with the cursor field of the t
+// struct: a field tagged `paging:"cursor"` sets both StructName and DBName
+// (from its gorm column), a StructName matching no field is looked up by
+// DBName column. Stores making their own cursors have no cursor field.
+func resolveCursorOptions(store Store, t reflect.Type, options *Options) (*Options, error) {
+	if _, ok := store.(NextCursorer); ok {
+		return options, nil
+	}
+
+	if t == nil {
+		return options, nil
+	}
+
+	var err error
+	cursorOptions := *options.CursorOptions
+
+	if field, ok := taggedCursorField(t); ok {
+		cursorOptions.StructName = field.Name
+		cursorOptions.DBName = columnName(field)
+	}
+
+	cursorOptions.StructName, err = resolveStructName(t, cursorOptions.StructName, cursorOptions.DBName)
+	if err != nil {
+		return nil, err
+	}
+
+	if cursorOptions.Nulls != "" {
+		cursorOptions.KeyStructName, err = resolveStructName(t, cursorOptions.KeyStructName, cursorOptions.KeyDBName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := *options
+	opts.CursorOptions = &cursorOptions
+
+	return &opts, nil
+}
+
+// itemStructType returns the struct type of items, nil when they aren't a
+// slice of structs or of pointers to structs.
+func itemStructType(items interface{}) reflect.Type {
+	t := reflect.TypeOf(items)
+	if t == nil {
+		return nil
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil
+	}
+
+	return structType(t.Elem())
+}
+
+// structType returns the struct type of t, a struct or a pointer to a
+// struct, nil otherwise.
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// taggedCursorField returns the field tagged `paging:"cursor"`, promoted
+// fields included.
+func taggedCursorField(t reflect.Type) (reflect.StructField, bool) {
+	for _, field := range reflect.VisibleFields(t) {
+		if tagName(field.Tag.Get("paging")) == CursorTag {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// resolveStructName returns structName when it's a field of t, or the name
+// of the field of the dbName column.
+func resolveStructName(t reflect.Type, structName string, dbName string) (string, error) {
+	_, err := getElementField(reflect.New(t).Elem(), structName)
+	if err == nil {
+		return structName, nil
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.Anonymous && field.IsExported() && sameColumn(columnName(field), dbName) {
+			return field.Name, nil
+		}
+	}
+
+	return "", err
+}
+
+// columnName returns the database column of a field, from its gorm column
+// tag or its name.
+func columnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(strings.ToUpper(setting), "COLUMN:") {
+			return strings.TrimSpace(setting[len("COLUMN:"):])
+		}
+	}
+	return gorm.ToColumnName(field.Name)
+}
@@ -0,0 +1,81 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Tagged struct {
+	Code string `gorm:"primary_key;column:user_code" paging:"cursor"`
+	Name string
+}
+
+type Column struct {
+	Reference string `gorm:"column:ref"`
+	CreatedAt time.Time
+}
+
+func TestResolveCursorOptions(t *testing.T) {
+	is := assert.New(t)
+
+	options, err := resolveCursorOptions(nil, itemStructType(&[]Tagged{}), NewOptions())
+	is.NoError(err)
+	is.Equal("Code", options.CursorOptions.StructName)
+	is.Equal("user_code", options.CursorOptions.DBName)
+
+	// the struct field is looked up by column
+	opts := NewOptions()
+	opts.CursorOptions.DBName = "ref"
+	options, err = resolveCursorOptions(nil, itemStructType(&[]*Column{}), opts)
+	is.NoError(err)
+	is.Equal("Reference", options.CursorOptions.StructName)
+	is.Equal("ID", opts.CursorOptions.StructName)
+
+	opts.CursorOptions.DBName = "created_at"
+	options, err = resolveCursorOptions(nil, itemStructType(&[]*Column{}), opts)
+	is.NoError(err)
+	is.Equal("CreatedAt", options.CursorOptions.StructName)
+
+	opts.CursorOptions.DBName = "missing"
+	_, err = resolveCursorOptions(nil, itemStructType(&[]*Column{}), opts)
+	is.True(errors.Is(err, ErrInvalidItems))
+}
+
+func TestCursorOptions_Type(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	// the cursor field is checked at construction
+	options := NewOptions()
+	options.CursorOptions.Type = reflect.TypeOf(Column{})
+	options.CursorOptions.DBName = "missing"
+	_, err = NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10}, options)
+	is.True(errors.Is(err, ErrInvalidItems))
+	_, err = NewTokenPaginator(store, 10, "", options)
+	is.True(errors.Is(err, ErrInvalidItems))
+
+	options.CursorOptions.Type = reflect.TypeOf(0)
+	_, err = NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10}, options)
+	is.True(errors.Is(err, ErrInvalidItems))
+
+	options = NewOptions()
+	options.CursorOptions.Type = reflect.TypeOf(&User{})
+	paginator, err := NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10}, options)
+	is.NoError(err)
+	is.Equal("ID", paginator.Options.CursorOptions.StructName)
+
+	users := []*User{}
+	is.NoError(paginator.Page(&users))
+	is.Len(users, 10)
+
+	// items must be of the declared type
+	is.True(errors.Is(paginator.Page(&[]Tagged{}), ErrInvalidItems))
+}
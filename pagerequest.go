@@ -0,0 +1,227 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSortNotSupported is returned when a page request is sorted and the
+// store doesn't implement Sorter.
+var ErrSortNotSupported = errors.New("store does not support sort")
+
+// ErrInvalidSort is returned when a page request is sorted by a field which
+// isn't in options' SortFields, or with an unknown direction.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// -----------------------------------------------------------------------------
+// Page request
+// -----------------------------------------------------------------------------
+
+// PageRequest is a page request, independent of the transport.
+type PageRequest struct {
+	// Limit is the number of items per page
+	Limit int64
+	// Offset is the offset of the first item, used by offset pagination
+	Offset int64
+	// Cursor is the cursor value, typed according to the cursor mode, used
+	// by cursor pagination (nil starts from the first item)
+	Cursor interface{}
+	// Sort is the database column to sort by, used by offset pagination
+	Sort string
+	// Direction is the sort direction (SortAsc or SortDesc), it reverses
+	// the cursor order with cursor pagination
+	Direction string
+	// Filters are the filters to apply to the store
+	Filters Filters
+
+	// ctx is the context of the HTTP request parsed, for logging
+	ctx context.Context
+	// clamped is true when the request limit was restricted to
+	// Options.MaxLimit
+	clamped bool
+}
+
+// DefaultPageRequest returns the page request of the first page.
+func DefaultPageRequest(options *Options) PageRequest {
+	if options == nil {
+		options = NewOptions()
+	}
+
+	return PageRequest{Limit: options.DefaultLimit}
+}
+
+// PageRequestFromHTTP returns the page request of an HTTP request, logged
+// with the request context.
+func PageRequestFromHTTP(request *http.Request, options *Options) (PageRequest, error) {
+	page, err := pageRequestFromValues(request.Context(), request.URL.Query(), options)
+	if err != nil {
+		return PageRequest{}, err
+	}
+
+	page.ctx = request.Context()
+	return page, nil
+}
+
+// PageRequestFromValues returns the page request of query string values.
+//
+// Invalid limit, offset and cursor fallback to their default values, a sort
+// field which isn't in options' SortFields is ignored.
+func PageRequestFromValues(values url.Values, options *Options) (PageRequest, error) {
+	return pageRequestFromValues(context.Background(), values, options)
+}
+
+// pageRequestFromValues returns the page request of query string values,
+// logged with ctx.
+func pageRequestFromValues(ctx context.Context, values url.Values, options *Options) (PageRequest, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+
+	page := PageRequest{
+		Limit:  getLimit(ctx, values, options),
+		Offset: getOffset(ctx, values, options),
+		Cursor: getCursorValue(ctx, values, options),
+	}
+
+	if limit, err := strconv.ParseInt(values.Get(options.LimitKeyName), 10, 64); err == nil && limit > page.Limit {
+		page.clamped = true
+	}
+
+	if sort := values.Get(options.SortKeyName); sort != "" {
+		for _, field := range options.SortFields {
+			if field == sort {
+				page.Sort = sort
+				break
+			}
+		}
+	}
+
+	switch direction := strings.ToLower(values.Get(options.DirectionKeyName)); direction {
+	case SortAsc, SortDesc:
+		page.Direction = direction
+	}
+
+	if options.FilterSpec != nil {
+		filters, err := options.FilterSpec.ParseValues(values)
+		if err != nil {
+			return PageRequest{}, err
+		}
+		page.Filters = filters
+	}
+
+	logDebug(ctx, options, "paging: page request",
+		"limit", page.Limit,
+		"offset", page.Offset,
+		"cursor", page.Cursor,
+		"sort", page.Sort,
+		"direction", page.Direction,
+		"filters", page.Filters.Values().Encode())
+
+	return page, nil
+}
+
+// PageRequestFromStruct returns the page request of a struct, like a CLI
+// flags or a message struct. Its fields are tagged with the query string key
+// names, e.g. `paging:"limit"`, nil and zero values are ignored.
+func PageRequestFromStruct(v interface{}, options *Options) (PageRequest, error) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return PageRequest{}, fmt.Errorf("can't get page request of a value of type %T", v)
+	}
+
+	values := url.Values{}
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("paging")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+
+		if field.IsZero() {
+			continue
+		}
+
+		if t, ok := field.Interface().(time.Time); ok {
+			// time in cursor is standard timestamp (second)
+			values.Set(key, strconv.FormatInt(t.Unix(), 10))
+			continue
+		}
+
+		values.Set(key, formatCursor(field.Interface()))
+	}
+
+	return PageRequestFromValues(values, options)
+}
+
+// context returns the context of the HTTP request parsed, or the background
+// context.
+func (r PageRequest) context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// validateSort checks the sort field is allowed by options and the direction
+// is SortAsc or SortDesc, the sort field is written as is in queries.
+func (r PageRequest) validateSort(options *Options) error {
+	if r.Direction != "" && r.Direction != SortAsc && r.Direction != SortDesc {
+		return fmt.Errorf("%w: direction %q", ErrInvalidSort, r.Direction)
+	}
+
+	if r.Sort == "" {
+		return nil
+	}
+
+	for _, field := range options.SortFields {
+		if field == r.Sort {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: field %q", ErrInvalidSort, r.Sort)
+}
+
+// cursorOptions returns options with the cursor order of the direction.
+func (r PageRequest) cursorOptions(options *Options) *Options {
+	if r.Direction == "" {
+		return options
+	}
+
+	cursorOptions := *options.CursorOptions
+	cursorOptions.Reverse = r.Direction == SortDesc
+
+	opts := *options
+	opts.CursorOptions = &cursorOptions
+
+	return &opts
+}
+
+// values returns the sort, direction and filters to keep in URIs.
+func (r PageRequest) values(options *Options) url.Values {
+	values := r.Filters.Values()
+
+	if r.Sort != "" {
+		values.Set(options.SortKeyName, r.Sort)
+	}
+
+	if r.Direction != "" {
+		values.Set(options.DirectionKeyName, r.Direction)
+	}
+
+	return values
+}
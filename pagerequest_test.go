@@ -0,0 +1,124 @@
+package paging
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageRequestFromValues(t *testing.T) {
+	is := assert.New(t)
+
+	options := NewOptions()
+	options.SortFields = []string{"name"}
+
+	page, err := PageRequestFromValues(url.Values{
+		"limit":     []string{"10"},
+		"offset":    []string{"30"},
+		"since":     []string{"42"},
+		"sort":      []string{"name"},
+		"direction": []string{"DESC"},
+	}, options)
+	is.NoError(err)
+	is.Equal(PageRequest{Limit: 10, Offset: 30, Cursor: int64(42), Sort: "name", Direction: SortDesc}, page)
+
+	// unknown sort field and direction are ignored
+	page, err = PageRequestFromValues(url.Values{
+		"sort":      []string{"password"},
+		"direction": []string{"up"},
+	}, options)
+	is.NoError(err)
+	is.Equal(PageRequest{Limit: 20, Cursor: int64(0)}, page)
+}
+
+func TestPageRequestFromStruct(t *testing.T) {
+	is := assert.New(t)
+
+	type listUsers struct {
+		PageSize  int64   `paging:"limit"`
+		Skip      *int64  `paging:"offset"`
+		OrderBy   string  `paging:"sort"`
+		Direction string  `paging:"direction"`
+		Query     *string `paging:"-"`
+	}
+
+	options := NewOptions()
+	options.SortFields = []string{"name"}
+
+	skip := int64(5)
+	page, err := PageRequestFromStruct(&listUsers{PageSize: 15, Skip: &skip, OrderBy: "name"}, options)
+	is.NoError(err)
+	is.Equal(PageRequest{Limit: 15, Offset: 5, Cursor: int64(0), Sort: "name"}, page)
+
+	page, err = PageRequestFromStruct(listUsers{}, options)
+	is.NoError(err)
+	is.Equal(DefaultPageRequest(options).Limit, page.Limit)
+
+	_, err = PageRequestFromStruct(1, options)
+	is.Error(err)
+}
+
+func TestNewOffsetPaginatorFromPageRequest(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	options := NewOptions()
+	options.SortFields = []string{"number"}
+
+	page := PageRequest{Limit: 10, Offset: 10, Sort: "number", Direction: SortDesc}
+	paginator, err := NewOffsetPaginatorFromPageRequest(store, page, options)
+	is.NoError(err)
+	is.Nil(paginator.Request)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal(90, users[0].Number)
+	is.Equal(int64(100), paginator.Count)
+	is.Equal("?limit=10&offset=20&direction=desc&sort=number", paginator.NextURI.String)
+	is.Equal("?limit=10&offset=0&direction=desc&sort=number", paginator.PreviousURI.String)
+
+	_, err = NewOffsetPaginatorFromPageRequest(nil, page, options)
+	is.Equal(ErrSortNotSupported, err)
+
+	// sort fields are written as is in queries
+	page.Sort = "number; DROP TABLE users"
+	_, err = NewOffsetPaginatorFromPageRequest(store, page, options)
+	is.True(errors.Is(err, ErrInvalidSort))
+
+	page.Sort, page.Direction = "number", "desc, id"
+	_, err = NewOffsetPaginatorFromPageRequest(store, page, options)
+	is.True(errors.Is(err, ErrInvalidSort))
+
+	_, err = NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10, Direction: "up"}, options)
+	is.True(errors.Is(err, ErrInvalidSort))
+}
+
+func TestNewCursorPaginatorFromPageRequest(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	options := NewOptions()
+	options.MaxLimit = 5
+
+	paginator, err := NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10, Direction: SortDesc}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal(int64(5), paginator.Limit)
+	is.Equal(100, users[0].ID)
+	is.Equal("?limit=5&since=96&direction=desc", paginator.NextURI.String)
+
+	// options are left untouched
+	is.False(options.CursorOptions.Reverse)
+}
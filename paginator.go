@@ -1,8 +1,12 @@
 package paging
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/guregu/null"
@@ -12,11 +16,12 @@ import (
 // Paginator interface
 // -----------------------------------------------------------------------------
 
-// Paginator is a paginator interface.
+// Paginator is a paginator interface. Pages are paginated into items, a
+// pointer to a slice.
 type Paginator interface {
-	Page() error
-	Previous() (Paginator, error)
-	Next() (Paginator, error)
+	Page(items interface{}) error
+	Previous(items interface{}) (Paginator, error)
+	Next(items interface{}) (Paginator, error)
 	HasPrevious() bool
 	HasNext() bool
 	MakePreviousURI() null.String
@@ -29,23 +34,104 @@ type paginator struct {
 	Store Store `json:"-"`
 	// Options are user options.
 	Options *Options `json:"-"`
-	// Request is the HTTP request
+	// Request is the HTTP request, nil when built from a PageRequest
 	Request *http.Request `json:"-"`
+	// Context is the context given to Options.Hooks, the request context
+	// when nil.
+	Context context.Context `json:"-"`
+
+	// Filters are the filters parsed from the request.
+	Filters Filters `json:"-"`
+	// Items are the items of the current page.
+	Items interface{} `json:"-"`
 
 	Limit   int64       `json:"limit"`
 	NextURI null.String `json:"next"`
+
+	// query are the request parameters kept in URIs
+	query url.Values
+	// clamped is true when the limit was restricted to Options.MaxLimit
+	clamped bool
+}
+
+// newPaginator returns the abstract paginator, with the page request filters
+// applied to the store.
+func newPaginator(store Store, page PageRequest, options *Options) (*paginator, error) {
+	p := &paginator{
+		Store:   store,
+		Options: options,
+		Filters: page.Filters,
+		Limit:   page.Limit,
+		query:   page.values(options),
+		clamped: page.clamped,
+	}
+
+	if options.MaxLimit > 0 && p.Limit > options.MaxLimit {
+		logDebug(page.context(), options, "paging: limit clamped to the maximum limit", "limit", p.Limit, "max_limit", options.MaxLimit)
+		p.Limit = options.MaxLimit
+		p.clamped = true
+	}
+
+	var err error
+	if p.Store, err = ApplyFilters(store, page.Filters); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// logURIs logs the generated previous and next URIs of a page.
+func (p *paginator) logURIs(ctx context.Context, previous null.String, err error) {
+	if err != nil {
+		logDebug(ctx, p.Options, "paging: can't generate the next URI", "error", err)
+		return
+	}
+
+	logDebug(ctx, p.Options, "paging: page URIs", "previous", previous.String, "next", p.NextURI.String)
+}
+
+// clone returns a copy of the paginator, so that paginating another page
+// doesn't change it.
+func (p *paginator) clone() *paginator {
+	c := *p
+	return &c
+}
+
+// appendQuery appends the request parameters to a generated pagination URI.
+func (p *paginator) appendQuery(uri string) string {
+	if len(p.query) == 0 || uri == "" {
+		return uri
+	}
+	return uri + "&" + p.query.Encode()
 }
 
 // -----------------------------------------------------------------------------
 // Paginator with cursor
 // -----------------------------------------------------------------------------
 
+// ErrSeekNotSupported is returned by the CursorPaginator's Seek methods when
+// the store doesn't implement Seeker.
+var ErrSeekNotSupported = errors.New("store does not support seek")
+
+// ErrInvalidCursor is returned by the CursorPaginator's Seek methods when the
+// value doesn't match the cursor mode.
+var ErrInvalidCursor = errors.New("invalid cursor value")
+
 // CursorPaginator is the paginator with cursor pagination system.
 type CursorPaginator struct {
 	*paginator
 	Cursor      interface{} `json:"-"`
 	PreviousURI null.String `json:"-"`
 	hasnext     bool
+	hasbefore   bool
+	seek        *seek
+	// next is the next cursor made by a NextCursorer store
+	next interface{}
+}
+
+// seek is the cursor value set by Seek or SeekAround.
+type seek struct {
+	before int64
 }
 
 // NewCursorPaginator returns a new CursorPaginator instance.
@@ -54,71 +140,349 @@ func NewCursorPaginator(store Store, request *http.Request, options *Options) (*
 		options = NewOptions()
 	}
 
+	page, err := PageRequestFromHTTP(request, options)
+	if err != nil {
+		return nil, err
+	}
+
+	paginator, err := NewCursorPaginatorFromPageRequest(store, page, options)
+	if err != nil {
+		return nil, err
+	}
+	paginator.Request = request
+
+	return paginator, nil
+}
+
+// NewCursorPaginatorFromPageRequest returns a new CursorPaginator instance
+// from a page request.
+func NewCursorPaginatorFromPageRequest(store Store, page PageRequest, options *Options) (*CursorPaginator, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+	if err := page.validateSort(options); err != nil {
+		return nil, err
+	}
+	options, err := declaredCursorOptions(store, page.cursorOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := newPaginator(store, page, options)
+	if err != nil {
+		return nil, err
+	}
+
 	paginator := &CursorPaginator{
+		paginator:   base,
+		Cursor:      page.Cursor,
+		PreviousURI: null.NewString("", false),
+	}
+
+	if paginator.Cursor == nil && options.CursorOptions.Nulls != "" {
+		paginator.Cursor = NullCursor{KeyDBName: options.CursorOptions.KeyDBName, Nulls: options.CursorOptions.Nulls}
+	}
+
+	return paginator, nil
+}
+
+// newCursorPaginator returns a CursorPaginator without request, starting from
+// the first item with the default limit.
+func newCursorPaginator(store Store, options *Options) (*CursorPaginator, error) {
+	options, err := declaredCursorOptions(store, options)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &CursorPaginator{
 		paginator: &paginator{
 			Store:   store,
 			Options: options,
-			Request: request,
-			Limit:   GetLimitFromRequest(request, options),
+			Limit:   options.DefaultLimit,
 		},
-		Cursor:      GetCursorFromRequest(request, options),
 		PreviousURI: null.NewString("", false),
 	}
 
-	if options.CursorOptions.Mode == DateModeCursor {
-		// time in cursor is standard timestamp (second)
-		paginator.Cursor = time.Unix(GetCursorFromRequest(request, options), 0)
+	if options.CursorOptions.Nulls != "" {
+		p.Cursor = NullCursor{KeyDBName: options.CursorOptions.KeyDBName, Nulls: options.CursorOptions.Nulls}
 	}
 
-	return paginator, nil
+	return p, nil
+}
+
+// setLimit sets the limit, restricted to the maximum limit.
+func (p *CursorPaginator) setLimit(limit int64) error {
+	if limit < 0 {
+		return ErrInvalidLimitOrOffset
+	}
+
+	p.Limit = limit
+	p.clamped = p.Options.MaxLimit > 0 && p.Limit > p.Options.MaxLimit
+	if p.clamped {
+		logDebug(p.hookContext(), p.Options, "paging: limit clamped to the maximum limit", "limit", p.Limit, "max_limit", p.Options.MaxLimit)
+		p.Limit = p.Options.MaxLimit
+	}
+
+	return nil
 }
 
-// Page searches and returns the items
-func (p *CursorPaginator) Page() error {
-	err := p.Store.PaginateCursor(
-		p.Limit,
-		p.Cursor,
-		p.Options.CursorOptions.DBName,
-		p.Options.CursorOptions.Reverse,
-		&p.hasnext)
+// setOpaqueCursor sets the cursor from an opaque cursor.
+func (p *CursorPaginator) setOpaqueCursor(cursor string) error {
+	value, err := decodeOpaqueCursor(p.Options.CursorOptions, cursor)
 	if err != nil {
 		return err
 	}
 
-	p.PreviousURI = p.MakePreviousURI()
-	p.NextURI = p.MakeNextURI()
+	p.Cursor = value
+	return nil
+}
+
+// Seek makes Page start from the given cursor value, included, instead of
+// the request cursor: a time.Time or a date string in date mode, an integer
+// ID (or its string form) in ID mode and a string or a fmt.Stringer (UUID,
+// ULID types) in string modes.
+func (p *CursorPaginator) Seek(value interface{}) error {
+	return p.SeekAround(value, 0)
+}
+
+// SeekAround is like Seek but Page also returns up to before items
+// preceding the value, followed by the page starting at the value.
+func (p *CursorPaginator) SeekAround(value interface{}, before int64) error {
+	if _, ok := p.Store.(Seeker); !ok {
+		return ErrSeekNotSupported
+	}
+
+	if before < 0 {
+		return ErrInvalidLimitOrOffset
+	}
+
+	cursor, err := parseSeekValue(p.Options.CursorOptions.Mode, value)
+	if err != nil {
+		return err
+	}
+
+	p.Cursor = cursor
+	p.seek = &seek{before: before}
 
 	return nil
 }
 
+// HasBefore returns true if items precede the page returned after a
+// SeekAround.
+func (p *CursorPaginator) HasBefore() bool {
+	return p.hasbefore
+}
+
+// Page searches the items into items. It returns ErrInvalidItems when the
+// items lack the cursor field.
+func (p *CursorPaginator) Page(items interface{}) error {
+	return p.observe(p.hookContext(), p.event(OperationPage), items, func(ctx context.Context) error {
+		return p.page(ctx, items)
+	})
+}
+
+// event returns the hook event of an operation on the page.
+func (p *CursorPaginator) event(operation string) *HookEvent {
+	return &HookEvent{
+		Operation:    operation,
+		Type:         CursorType,
+		Limit:        p.Limit,
+		LimitClamped: p.clamped,
+		Cursor:       p.Cursor,
+	}
+}
+
+// page searches the items into items, ctx is the hooks context of the store
+// calls.
+func (p *CursorPaginator) page(ctx context.Context, items interface{}) error {
+	options, err := itemCursorOptions(p.Store, items, p.Options)
+	if err != nil {
+		return err
+	}
+	p.Options = options
+	p.Items = items
+
+	if p.seek != nil {
+		return p.pageSeek(ctx)
+	}
+
+	if err := p.paginate(ctx); err != nil {
+		return err
+	}
+
+	p.PreviousURI = p.MakePreviousURI()
+	p.NextURI, err = p.makeNextURI()
+	p.logURIs(ctx, p.PreviousURI, err)
+
+	return err
+}
+
+// paginate paginates the items from the cursor, with the next cursor when
+// the store is a NextCursorer.
+func (p *CursorPaginator) paginate(ctx context.Context) error {
+	options := p.Options.CursorOptions
+
+	return p.observe(ctx, p.event(OperationPaginateCursor), p.Items, func(ctx context.Context) error {
+		store := p.contextStore(ctx)
+		if store, ok := store.(NextCursorer); ok {
+			return store.PaginateNextCursor(p.Items, p.Limit, p.Cursor, options.DBName, options.Reverse, &p.hasnext, &p.next)
+		}
+
+		return store.PaginateCursor(p.Items, p.Limit, p.Cursor, options.DBName, options.Reverse, &p.hasnext)
+	})
+}
+
+// pageSeek searches the items around the seek value.
+func (p *CursorPaginator) pageSeek(ctx context.Context) error {
+	var (
+		options = p.Options.CursorOptions
+		before  interface{}
+	)
+
+	if p.seek.before > 0 {
+		before = newItems(p.Items)
+
+		event := p.event(OperationPaginateSeek)
+		event.Limit = p.seek.before
+
+		err := p.observe(ctx, event, before, func(ctx context.Context) error {
+			return p.contextStore(ctx).(Seeker).PaginateSeek(before, p.seek.before, p.Cursor, options.DBName, options.Reverse, true, &p.hasbefore)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := p.observe(ctx, p.event(OperationPaginateSeek), p.Items, func(ctx context.Context) error {
+		return p.contextStore(ctx).(Seeker).PaginateSeek(p.Items, p.Limit, p.Cursor, options.DBName, options.Reverse, false, &p.hasnext)
+	})
+	if err != nil {
+		return err
+	}
+
+	if before != nil {
+		elements, err := sliceValue(before)
+		if err != nil {
+			return err
+		}
+		if err := prependElements(p.Items, elements); err != nil {
+			return err
+		}
+	}
+
+	p.PreviousURI = p.MakePreviousURI()
+	p.NextURI, err = p.makeNextURI()
+	p.logURIs(ctx, p.PreviousURI, err)
+
+	return err
+}
+
+// parseSeekValue converts a seek value to a cursor of the given mode.
+func parseSeekValue(mode string, value interface{}) (interface{}, error) {
+	if mode == DateModeCursor {
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			t, err := parseTime(v)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			return t, nil
+		}
+		return nil, ErrInvalidCursor
+	}
+
+	if isStringCursorMode(mode) {
+		if value == nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor, err := parseCursor(mode, formatCursor(value))
+		if err != nil || cursor == "" {
+			return nil, ErrInvalidCursor
+		}
+		return cursor, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), nil
+	case reflect.String:
+		id, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return id, nil
+	}
+
+	return nil, ErrInvalidCursor
+}
+
 // Previous is not available on cursor system
-func (p *CursorPaginator) Previous() (Paginator, error) {
+func (p *CursorPaginator) Previous(items interface{}) (Paginator, error) {
 	return nil, errors.New("No previous page")
 }
 
-// Next returns next items
-func (p *CursorPaginator) Next() (Paginator, error) {
+// Next returns the paginator of the next page, searched into items.
+func (p *CursorPaginator) Next(items interface{}) (Paginator, error) {
 	if !p.HasNext() {
 		return nil, errors.New("No next page")
 	}
 
-	np := *p
-	np.Cursor = getLastElementField(p.Store.GetItems(), np.Options.CursorOptions.StructName)
-	err := np.Store.PaginateCursor(
-		np.Limit,
-		np.Cursor,
-		np.Options.CursorOptions.DBName,
-		np.Options.CursorOptions.Reverse,
-		&np.hasnext)
+	cursor, err := p.nextCursor()
 	if err != nil {
 		return nil, err
 	}
 
-	np.NextURI = p.MakeNextURI()
+	np := *p
+	np.paginator = p.clone()
+	np.seek = nil
+	np.hasbefore = false
+	np.Cursor = cursor
+
+	err = np.observe(np.hookContext(), np.event(OperationNext), items, func(ctx context.Context) error {
+		return np.page(ctx, items)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &np, nil
 }
 
+// nextCursor returns the cursor of the last item, a NullCursor when
+// CursorOptions.Nulls is set, or the store's one when it's a NextCursorer.
+func (p *CursorPaginator) nextCursor() (interface{}, error) {
+	if _, ok := p.Store.(NextCursorer); ok {
+		return p.next, nil
+	}
+
+	options := p.Options.CursorOptions
+	items := p.Items
+
+	cursor, err := getLastElementCursor(items, options.StructName)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Nulls != "" {
+		nc := NullCursor{Value: cursor, KeyDBName: options.KeyDBName, Nulls: options.Nulls}
+		if cursor == nil {
+			nc.Key, err = getLastElementCursor(items, options.KeyStructName)
+		}
+		return nc, err
+	}
+
+	if cursor != nil && isStringCursorMode(options.Mode) {
+		return formatCursor(cursor), nil
+	}
+
+	return cursor, nil
+}
+
 // HasPrevious returns false, previous page is not available on cursor system
 func (CursorPaginator) HasPrevious() bool {
 	return false
@@ -134,21 +498,39 @@ func (CursorPaginator) MakePreviousURI() null.String {
 	return null.NewString("", false)
 }
 
-// MakeNextURI returns the next page URI.
+// MakeNextURI returns the next page URI, a null string when the items
+// have no valid cursor.
 func (p *CursorPaginator) MakeNextURI() null.String {
+	uri, _ := p.makeNextURI()
+	return uri
+}
+
+// makeNextURI returns the next page URI, or an error when the items have no
+// valid cursor.
+func (p *CursorPaginator) makeNextURI() (null.String, error) {
 	if !p.HasNext() {
-		return null.NewString("", false)
+		return null.NewString("", false), nil
 	}
 
-	nextCursor := getLastElementField(p.Store.GetItems(), p.Options.CursorOptions.StructName)
+	nextCursor, err := p.nextCursor()
+	if err != nil {
+		return null.NewString("", false), err
+	}
+
+	if nc, ok := nextCursor.(NullCursor); ok {
+		if nc.Value == nil {
+			return null.StringFrom(p.appendQuery(GenerateCursorURI(p.Limit, nc, p.Options))), nil
+		}
+		nextCursor = nc.Value
+	}
 
 	if nextCursor == nil {
-		return null.NewString("", false)
+		return null.NewString("", false), nil
 	}
 
 	// convert to timestamp
-	if p.Options.CursorOptions.Mode == DateModeCursor {
-		timestamp := nextCursor.(time.Time).Unix()
+	if t, ok := nextCursor.(time.Time); ok && p.Options.CursorOptions.Mode == DateModeCursor {
+		timestamp := t.Unix()
 		if !p.Options.CursorOptions.Reverse {
 			// The next cursor must be the timestamp of the last item incremented by one.
 			// Otherwise, we would get duplicates as the last item of the current page would be included
@@ -163,7 +545,7 @@ func (p *CursorPaginator) MakeNextURI() null.String {
 		nextCursor = timestamp
 	}
 
-	return null.StringFrom(GenerateCursorURI(p.Limit, nextCursor, p.Options))
+	return null.StringFrom(p.appendQuery(GenerateCursorURI(p.Limit, nextCursor, p.Options))), nil
 }
 
 // -----------------------------------------------------------------------------
@@ -174,12 +556,26 @@ func (p *CursorPaginator) MakeNextURI() null.String {
 // indicate that the limit or the offset is invalid
 var ErrInvalidLimitOrOffset = errors.New("invalid limit or offset")
 
+// ErrMaxOffsetExceeded is returned by the OffsetPaginator's Page method when
+// the offset is past Options.MaxOffset, and isn't a keyset page with
+// Options.KeysetFallback.
+var ErrMaxOffsetExceeded = errors.New("offset exceeds the maximum offset")
+
 // OffsetPaginator is the paginator with offset pagination system.
+//
+// With Options.KeysetFallback, pages past Options.MaxOffset are keyset
+// pages: they're searched from the cursor of the previous page, which the
+// next URI holds along with the offset. Keyset pages have no previous page
+// and no count, and the store must be ordered by the cursor. Stores making
+// their own cursors end at Options.MaxOffset.
 type OffsetPaginator struct {
 	*paginator
 	Offset      int64       `json:"offset"`
 	Count       int64       `json:"total_count"`
 	PreviousURI null.String `json:"previous"`
+	// Cursor is the cursor of a keyset page
+	Cursor  interface{} `json:"-"`
+	hasnext bool
 }
 
 // NewOffsetPaginator returns a new OffsetPaginator instance.
@@ -188,88 +584,283 @@ func NewOffsetPaginator(store Store, request *http.Request, options *Options) (*
 		options = NewOptions()
 	}
 
-	return &OffsetPaginator{
-		&paginator{
-			Store:   store,
-			Options: options,
-			Request: request,
-			Limit:   GetLimitFromRequest(request, options),
-		},
-		GetOffsetFromRequest(request, options),
-		0,
-		null.NewString("", false),
-	}, nil
+	page, err := PageRequestFromHTTP(request, options)
+	if err != nil {
+		return nil, err
+	}
+
+	paginator, err := NewOffsetPaginatorFromPageRequest(store, page, options)
+	if err != nil {
+		return nil, err
+	}
+	paginator.Request = request
+
+	return paginator, nil
+}
+
+// NewOffsetPaginatorFromPageRequest returns a new OffsetPaginator instance
+// from a page request.
+func NewOffsetPaginatorFromPageRequest(store Store, page PageRequest, options *Options) (*OffsetPaginator, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+	if err := page.validateSort(options); err != nil {
+		return nil, err
+	}
+	options, err := declaredCursorOptions(store, page.cursorOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := newPaginator(store, page, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Sort != "" {
+		sorter, ok := base.Store.(Sorter)
+		if !ok {
+			return nil, ErrSortNotSupported
+		}
+
+		if base.Store, err = sorter.Sort(page.Sort, page.Direction == SortDesc); err != nil {
+			return nil, err
+		}
+	}
+
+	paginator := &OffsetPaginator{
+		paginator:   base,
+		Offset:      page.Offset,
+		PreviousURI: null.NewString("", false),
+	}
+
+	if paginator.isKeyset(paginator.Offset) && hasCursor(page.Cursor) {
+		paginator.Cursor = page.Cursor
+	}
+
+	return paginator, nil
+}
+
+// Page searches the items into items. It returns ErrMaxOffsetExceeded when
+// the offset is past Options.MaxOffset without a keyset cursor.
+func (p *OffsetPaginator) Page(items interface{}) error {
+	return p.observePage(OperationPage, items)
+}
+
+// event returns the hook event of an operation on the page.
+func (p *OffsetPaginator) event(operation string) *HookEvent {
+	return &HookEvent{
+		Operation:    operation,
+		Type:         OffsetType,
+		Limit:        p.Limit,
+		LimitClamped: p.clamped,
+		Offset:       p.Offset,
+		Cursor:       p.Cursor,
+	}
+}
+
+// observePage searches the items into items as the operation of the hooks.
+func (p *OffsetPaginator) observePage(operation string, items interface{}) error {
+	event := p.event(operation)
+
+	return p.observe(p.hookContext(), event, items, func(ctx context.Context) error {
+		err := p.page(ctx, items)
+		event.Count = p.Count
+		return err
+	})
 }
 
-// Page searches and returns the items
-func (p *OffsetPaginator) Page() error {
+// page searches the items into items, ctx is the hooks context of the store
+// calls.
+func (p *OffsetPaginator) page(ctx context.Context, items interface{}) error {
 	if !ValidateLimitOffset(p.Limit, p.Offset) {
 		return ErrInvalidLimitOrOffset
 	}
 
-	if err := p.Store.PaginateOffset(p.Limit, p.Offset, &p.Count); err != nil {
+	if p.Options.MaxOffset > 0 && p.Offset > p.Options.MaxOffset {
+		if !p.isKeyset(p.Offset) || p.Cursor == nil {
+			return ErrMaxOffsetExceeded
+		}
+		return p.pageKeyset(ctx, items)
+	}
+
+	if err := p.paginate(ctx, items); err != nil {
 		return err
 	}
+	p.Items = items
 
+	var err error
 	p.PreviousURI = p.MakePreviousURI()
-	p.NextURI = p.MakeNextURI()
+	p.NextURI, err = p.makeNextURI()
+	p.logURIs(ctx, p.PreviousURI, err)
 
-	return nil
+	return err
 }
 
-// Previous returns previous items
-func (p *OffsetPaginator) Previous() (Paginator, error) {
+// paginate paginates the items with the offset. With hooks, the count of a
+// CountObserver store is observed apart.
+func (p *OffsetPaginator) paginate(ctx context.Context, items interface{}) error {
+	event := p.event(OperationPaginateOffset)
+	return p.observe(ctx, event, items, func(ctx context.Context) error {
+		store := p.contextStore(ctx)
+		if observer, ok := store.(CountObserver); ok && len(p.Options.Hooks) > 0 {
+			store = observer.ObserveCount(func(call func() error) error {
+				count := p.event(OperationCount)
+				return p.observe(ctx, count, nil, func(context.Context) error {
+					err := call()
+					count.Count = p.Count
+					return err
+				})
+			})
+		}
+
+		err := store.PaginateOffset(items, p.Limit, p.Offset, &p.Count)
+		event.Count = p.Count
+		return err
+	})
+}
+
+// pageKeyset searches the items of a keyset page into items.
+func (p *OffsetPaginator) pageKeyset(ctx context.Context, items interface{}) error {
+	keyset, err := p.keyset(items)
+	if err != nil {
+		return err
+	}
+
+	if err := keyset.paginate(ctx); err != nil {
+		return err
+	}
+	p.Items = items
+	p.hasnext = keyset.hasnext
+
+	p.PreviousURI = null.NewString("", false)
+	p.NextURI, err = p.makeNextURI()
+	p.logURIs(ctx, p.PreviousURI, err)
+
+	return err
+}
+
+// isKeyset returns true if the page at offset is a keyset page.
+func (p *OffsetPaginator) isKeyset(offset int64) bool {
+	return p.Options.KeysetFallback && p.Options.MaxOffset > 0 && offset > p.Options.MaxOffset
+}
+
+// keyset returns the cursor paginator of the page items, its URIs keep the
+// offset of the next page.
+func (p *OffsetPaginator) keyset(items interface{}) (*CursorPaginator, error) {
+	options, err := itemCursorOptions(p.Store, items, p.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	base := p.clone()
+	base.Options = options
+	base.Items = items
+
+	base.query = url.Values{}
+	for key, values := range p.query {
+		base.query[key] = values
+	}
+	base.query.Set(options.OffsetKeyName, strconv.FormatInt(p.Offset+p.Limit, 10))
+
+	return &CursorPaginator{
+		paginator:   base,
+		Cursor:      p.Cursor,
+		PreviousURI: null.NewString("", false),
+		hasnext:     p.HasNext(),
+	}, nil
+}
+
+// hasCursor returns true if a request cursor isn't the first item one.
+func hasCursor(cursor interface{}) bool {
+	switch c := cursor.(type) {
+	case nil:
+		return false
+	case NullCursor:
+		return c.Value != nil || c.Key != nil
+	case string:
+		return c != ""
+	case int64:
+		return c > 0
+	case time.Time:
+		return c.Unix() > 0
+	}
+	return true
+}
+
+// Previous returns the paginator of the previous page, searched into items.
+func (p *OffsetPaginator) Previous(items interface{}) (Paginator, error) {
 	if !p.HasPrevious() {
 		return nil, errors.New("No previous page")
 	}
 
 	paginator := *p
+	paginator.paginator = p.clone()
 
 	paginator.Offset = p.Offset - p.Limit
 
-	if err := paginator.Store.PaginateOffset(paginator.Limit, paginator.Offset, &paginator.Count); err != nil {
+	if err := paginator.observePage(OperationPrevious, items); err != nil {
 		return nil, err
 	}
 
-	paginator.PreviousURI = p.MakePreviousURI()
-	paginator.NextURI = p.MakeNextURI()
-
 	return &paginator, nil
 }
 
-// Next returns next items
-func (p *OffsetPaginator) Next() (Paginator, error) {
+// Next returns the paginator of the next page, searched into items.
+func (p *OffsetPaginator) Next(items interface{}) (Paginator, error) {
 	if !p.HasNext() {
 		return nil, errors.New("No next page")
 	}
 
 	paginator := *p
+	paginator.paginator = p.clone()
 
 	paginator.Offset = p.Offset + p.Limit
 
-	if err := paginator.Store.PaginateOffset(paginator.Limit, paginator.Offset, &paginator.Count); err != nil {
-		return nil, err
+	if p.isKeyset(paginator.Offset) {
+		keyset, err := p.keyset(p.Items)
+		if err != nil {
+			return nil, err
+		}
+
+		if paginator.Cursor, err = keyset.nextCursor(); err != nil {
+			return nil, err
+		}
 	}
 
-	paginator.PreviousURI = p.MakePreviousURI()
-	paginator.NextURI = p.MakeNextURI()
+	if err := paginator.observePage(OperationNext, items); err != nil {
+		return nil, err
+	}
 
 	return &paginator, nil
 }
 
 // HasPrevious returns true if there is a previous page.
 func (p *OffsetPaginator) HasPrevious() bool {
+	if p.isKeyset(p.Offset) {
+		return false
+	}
+
 	if (p.Offset - p.Limit) < 0 {
 		return false
 	}
 	return true
 }
 
-// HasNext returns true if has next page.
+// HasNext returns true if has next page, false when its offset is past
+// Options.MaxOffset without keyset fallback.
 func (p *OffsetPaginator) HasNext() bool {
+	if p.isKeyset(p.Offset) {
+		return p.hasnext
+	}
+
 	if (p.Offset + p.Limit) >= p.Count {
 		return false
 	}
+
+	if p.Options.MaxOffset > 0 && !p.Options.KeysetFallback && (p.Offset+p.Limit) > p.Options.MaxOffset {
+		return false
+	}
 	return true
 }
 
@@ -279,14 +870,30 @@ func (p *OffsetPaginator) MakePreviousURI() null.String {
 		return null.NewString("", false)
 	}
 
-	return null.StringFrom(GenerateOffsetURI(p.Limit, (p.Offset - p.Limit), p.Options))
+	return null.StringFrom(p.appendQuery(GenerateOffsetURI(p.Limit, (p.Offset - p.Limit), p.Options)))
 }
 
-// MakeNextURI returns the next page URI.
+// MakeNextURI returns the next page URI, a null string when the next page
+// is a keyset page and the items have no valid cursor.
 func (p *OffsetPaginator) MakeNextURI() null.String {
+	uri, _ := p.makeNextURI()
+	return uri
+}
+
+// makeNextURI returns the next page URI, with the cursor of the last item
+// when the next page is a keyset page.
+func (p *OffsetPaginator) makeNextURI() (null.String, error) {
 	if !p.HasNext() {
-		return null.NewString("", false)
+		return null.NewString("", false), nil
+	}
+
+	if p.isKeyset(p.Offset + p.Limit) {
+		keyset, err := p.keyset(p.Items)
+		if err != nil {
+			return null.NewString("", false), err
+		}
+		return keyset.makeNextURI()
 	}
 
-	return null.StringFrom(GenerateOffsetURI(p.Limit, (p.Offset + p.Limit), p.Options))
+	return null.StringFrom(p.appendQuery(GenerateOffsetURI(p.Limit, (p.Offset + p.Limit), p.Options))), nil
 }
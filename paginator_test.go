@@ -1,6 +1,8 @@
 package paging
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -37,7 +39,7 @@ func TestCursorPaginator_Next_DateMode(t *testing.T) {
 	is.NoError(db.Create(&User{DateCreation: ts.Add(time.Second)}).Error)
 
 	var users []User
-	s, err := NewGORMStore(db.Model(u).Order("date_creation"), &users)
+	s, err := NewGORMStore(db.Model(u).Order("date_creation"))
 	is.NoError(err)
 
 	v := url.Values{"limit": []string{"1"}, "since": []string{"1"}}
@@ -47,7 +49,7 @@ func TestCursorPaginator_Next_DateMode(t *testing.T) {
 	opts.CursorOptions.StructName = "DateCreation"
 	p, err := NewCursorPaginator(s, &http.Request{URL: &url.URL{RawQuery: v.Encode()}}, opts)
 	is.NoError(err)
-	is.NoError(p.Page())
+	is.NoError(p.Page(&users))
 	is.Len(users, 1)
 	is.Equal(1, users[0].ID)
 
@@ -68,7 +70,7 @@ func TestCursorPaginator_Next_DateMode_Reverse(t *testing.T) {
 	is.NoError(db.Create(&User{DateCreation: ts.Add(time.Second)}).Error)
 
 	var users []User
-	s, err := NewGORMStore(db.Model(u).Order("date_creation desc"), &users)
+	s, err := NewGORMStore(db.Model(u).Order("date_creation desc"))
 	is.NoError(err)
 
 	since := strconv.FormatInt(time.Now().Unix(), 10)
@@ -80,7 +82,7 @@ func TestCursorPaginator_Next_DateMode_Reverse(t *testing.T) {
 	opts.CursorOptions.Reverse = true
 	p, err := NewCursorPaginator(s, &http.Request{URL: &url.URL{RawQuery: v.Encode()}}, opts)
 	is.NoError(err)
-	is.NoError(p.Page())
+	is.NoError(p.Page(&users))
 	is.Len(users, 1)
 	is.Equal(2, users[0].ID)
 
@@ -89,3 +91,221 @@ func TestCursorPaginator_Next_DateMode_Reverse(t *testing.T) {
 	// the next uri cursor is the timestamp of the last element
 	is.Contains(next.String, strconv.FormatInt(users[0].DateCreation.Unix(), 10))
 }
+
+func TestCursorPaginator_Seek(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=10", nil)
+	p, err := NewCursorPaginator(store, request, nil)
+	is.NoError(err)
+
+	is.Equal(ErrInvalidCursor, p.Seek(time.Now()))
+	is.NoError(p.Seek("42"))
+	is.NoError(p.Page(&users))
+	is.Len(users, 10)
+	is.Equal(42, users[0].ID)
+	is.Equal(51, users[9].ID)
+	is.False(p.HasBefore())
+	is.Equal("?limit=10&since=51", p.NextURI.String)
+
+	np, err := p.Next(&[]User{})
+	is.NoError(err)
+	is.Equal(52, (*pageItems(np).(*[]User))[0].ID)
+	is.True(np.HasNext())
+
+	is.NoError(p.SeekAround(95, 3))
+	is.NoError(p.Page(&users))
+	is.Len(users, 9)
+	is.Equal(92, users[0].ID)
+	is.Equal(95, users[3].ID)
+	is.Equal(100, users[8].ID)
+	is.True(p.HasBefore())
+	is.False(p.HasNext())
+}
+
+func TestCursorPaginator_SeekAround_DateMode_Reverse(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	opts := NewOptions()
+	opts.CursorOptions.Mode = DateModeCursor
+	opts.CursorOptions.DBName = "date_creation"
+	opts.CursorOptions.StructName = "DateCreation"
+	opts.CursorOptions.Reverse = true
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=5", nil)
+	p, err := NewCursorPaginator(store, request, opts)
+	is.NoError(err)
+
+	// user 51 is created 50 minutes before refDate
+	target := time.Unix(refDate, 0).Add(-50 * time.Minute)
+	is.NoError(p.SeekAround(target, 2))
+	is.NoError(p.Page(&users))
+	is.Len(users, 7)
+	is.Equal([]int{53, 52, 51, 50, 49, 48, 47}, []int{
+		users[0].ID, users[1].ID, users[2].ID, users[3].ID, users[4].ID, users[5].ID, users[6].ID,
+	})
+	is.True(p.HasBefore())
+	is.True(p.HasNext())
+	is.Equal(fmt.Sprintf("?limit=5&since=%d", users[6].DateCreation.Unix()), p.NextURI.String)
+}
+
+func TestCursorPaginator_Items(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []*User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	paginator, err := NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal("?limit=10&since=10", paginator.NextURI.String)
+
+	np, err := paginator.Next(&[]*User{})
+	is.NoError(err)
+	is.Equal(11, (*pageItems(np).(*[]*User))[0].ID)
+	is.Equal("?limit=10&since=20", np.(*CursorPaginator).NextURI.String)
+
+	// the cursor field is validated against the items of the page
+	options := NewOptions()
+	options.CursorOptions.StructName = "Missing"
+	options.CursorOptions.DBName = "missing"
+	paginator, err = NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 10}, options)
+	is.NoError(err)
+	err = paginator.Page(&users)
+	is.True(errors.Is(err, ErrInvalidItems))
+	is.Equal(`invalid items: no field "Missing" in paging.User`, err.Error())
+}
+
+func TestOffsetPaginator_Next_Previous(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	paginator, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	np, err := paginator.Next(&[]User{})
+	is.NoError(err)
+	pp, err := paginator.Previous(&[]User{})
+	is.NoError(err)
+
+	// every page holds its own items and links
+	is.Equal(11, users[0].ID)
+	is.Equal(21, (*pageItems(np).(*[]User))[0].ID)
+	is.Equal(1, (*pageItems(pp).(*[]User))[0].ID)
+
+	is.Equal("?limit=10&offset=20", paginator.NextURI.String)
+	is.Equal("?limit=10&offset=30", np.(*OffsetPaginator).NextURI.String)
+	is.Equal("?limit=10&offset=10", np.(*OffsetPaginator).PreviousURI.String)
+	is.Equal("?limit=10&offset=10", pp.(*OffsetPaginator).NextURI.String)
+	is.False(pp.(*OffsetPaginator).PreviousURI.Valid)
+}
+
+func TestOffsetPaginator_MaxOffset(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	options := NewOptions()
+	options.MaxOffset = 50
+
+	users := []User{}
+	paginator, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10, Offset: 50}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(51, users[0].ID)
+	is.True(paginator.HasPrevious())
+	is.False(paginator.HasNext())
+	is.False(paginator.NextURI.Valid)
+
+	// the last page within the maximum offset links to the next one
+	paginator, err = NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10, Offset: 40}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal("?limit=10&offset=50", paginator.NextURI.String)
+
+	// a deeper offset is rejected
+	paginator, err = NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10, Offset: 60}, options)
+	is.NoError(err)
+	is.Equal(ErrMaxOffsetExceeded, paginator.Page(&users))
+
+	// a cursor doesn't bypass the maximum offset without keyset fallback
+	request, _ := http.NewRequest("GET", "http://example.com?limit=10&offset=60&since=60", nil)
+	paginator, err = NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.Equal(ErrMaxOffsetExceeded, paginator.Page(&users))
+}
+
+func TestOffsetPaginator_KeysetFallback(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	options := NewOptions()
+	options.MaxOffset = 80
+	options.KeysetFallback = true
+
+	users := []User{}
+	paginator, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 10, Offset: 70}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal("?limit=10&offset=80", paginator.NextURI.String)
+
+	// the last offset page links to a keyset page
+	np, err := paginator.Next(&[]User{})
+	is.NoError(err)
+	is.Equal(81, (*pageItems(np).(*[]User))[0].ID)
+	is.Equal("?limit=10&since=90&offset=90", np.MakeNextURI().String)
+	is.True(np.HasPrevious())
+
+	// keyset pages are searched from the cursor of the URI
+	request, _ := http.NewRequest("GET", "http://example.com"+np.MakeNextURI().String, nil)
+	keyset, err := NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(keyset.Page(&users))
+	is.Equal(int64(90), keyset.Offset)
+	is.Equal(int64(90), keyset.Cursor)
+	is.Equal(91, users[0].ID)
+	is.Len(users, 10)
+	is.False(keyset.HasNext())
+	is.False(keyset.NextURI.Valid)
+	is.False(keyset.PreviousURI.Valid)
+
+	// the keyset page of Next matches the one of the URI
+	kp, err := np.Next(&[]User{})
+	is.NoError(err)
+	is.Equal(91, (*pageItems(kp).(*[]User))[0].ID)
+	is.False(kp.HasNext())
+
+	// deep offsets without cursor are still rejected
+	request, _ = http.NewRequest("GET", "http://example.com?limit=10&offset=5000000", nil)
+	paginator, err = NewOffsetPaginator(store, request, options)
+	is.NoError(err)
+	is.Equal(ErrMaxOffsetExceeded, paginator.Page(&users))
+}
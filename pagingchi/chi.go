@@ -0,0 +1,35 @@
+// Package pagingchi binds paging to the chi router and its render package.
+package pagingchi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/ulule/paging"
+)
+
+// PageRequest returns the page request from the request query string.
+func PageRequest(r *http.Request, options *paging.Options) (paging.PageRequest, error) {
+	return paging.PageRequestFromHTTP(r, options)
+}
+
+// NewOffsetPaginator returns a new OffsetPaginator instance from the request.
+func NewOffsetPaginator(r *http.Request, store paging.Store, options *paging.Options) (*paging.OffsetPaginator, error) {
+	return paging.NewOffsetPaginator(store, r, options)
+}
+
+// NewCursorPaginator returns a new CursorPaginator instance from the request.
+func NewCursorPaginator(r *http.Request, store paging.Store, options *paging.Options) (*paging.CursorPaginator, error) {
+	return paging.NewCursorPaginator(store, r, options)
+}
+
+// Render sends the items and the paginator with render.Respond, with the
+// next and previous page links in the Link header.
+func Render(w http.ResponseWriter, r *http.Request, code int, paginator paging.Paginator, items interface{}) {
+	if link := paging.LinkHeader(paginator, r.URL.Path); link != "" {
+		w.Header().Set("Link", link)
+	}
+
+	render.Status(r, code)
+	render.Respond(w, r, paging.NewResponse(paginator, items))
+}
@@ -0,0 +1,32 @@
+package pagingchi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+func TestRender(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/numbers", func(w http.ResponseWriter, r *http.Request) {
+		var numbers []int
+		paginator, err := NewOffsetPaginator(r, pagingtest.NumberStore{}, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := paginator.Page(&numbers); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		Render(w, r, http.StatusOK, paginator, numbers)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, pagingtest.RenderRequest())
+
+	pagingtest.AssertRender(t, rec)
+}
@@ -0,0 +1,32 @@
+// Package pagingecho binds paging to the echo web framework.
+package pagingecho
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/ulule/paging"
+)
+
+// PageRequest returns the page request from the echo context query string.
+func PageRequest(c echo.Context, options *paging.Options) (paging.PageRequest, error) {
+	return paging.PageRequestFromHTTP(c.Request(), options)
+}
+
+// NewOffsetPaginator returns a new OffsetPaginator instance from the echo context.
+func NewOffsetPaginator(c echo.Context, store paging.Store, options *paging.Options) (*paging.OffsetPaginator, error) {
+	return paging.NewOffsetPaginator(store, c.Request(), options)
+}
+
+// NewCursorPaginator returns a new CursorPaginator instance from the echo context.
+func NewCursorPaginator(c echo.Context, store paging.Store, options *paging.Options) (*paging.CursorPaginator, error) {
+	return paging.NewCursorPaginator(store, c.Request(), options)
+}
+
+// Render sends the items and the paginator as JSON, with the next and
+// previous page links in the Link header.
+func Render(c echo.Context, code int, paginator paging.Paginator, items interface{}) error {
+	if link := paging.LinkHeader(paginator, c.Request().URL.Path); link != "" {
+		c.Response().Header().Set("Link", link)
+	}
+
+	return c.JSON(code, paging.NewResponse(paginator, items))
+}
@@ -0,0 +1,42 @@
+package pagingecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+func TestRender(t *testing.T) {
+	e := echo.New()
+	e.GET("/numbers", func(c echo.Context) error {
+		var numbers []int
+		paginator, err := NewOffsetPaginator(c, pagingtest.NumberStore{}, nil)
+		if err != nil {
+			return err
+		}
+		if err := paginator.Page(&numbers); err != nil {
+			return err
+		}
+		return Render(c, http.StatusOK, paginator, numbers)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, pagingtest.RenderRequest())
+
+	pagingtest.AssertRender(t, rec)
+}
+
+func TestPageRequest(t *testing.T) {
+	is := assert.New(t)
+
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/?limit=5&since=10", nil), httptest.NewRecorder())
+	page, err := PageRequest(c, paging.NewOptions())
+	is.NoError(err)
+	is.Equal(int64(5), page.Limit)
+	is.Equal(int64(10), page.Cursor)
+}
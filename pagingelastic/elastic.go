@@ -0,0 +1,382 @@
+// Package pagingelastic provides a paging store for Elasticsearch and
+// OpenSearch indices.
+package pagingelastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/ulule/paging"
+)
+
+// DefaultKeepAlive is the default point in time keep alive.
+const DefaultKeepAlive = "1m"
+
+// errInvalidCursor is returned by the ElasticStore's PaginateCursor method
+// when the cursor isn't a cursor made by the store.
+var errInvalidCursor = fmt.Errorf("%w: elasticsearch cursor", paging.ErrInvalidCursor)
+
+// -----------------------------------------------------------------------------
+// Elastic Store
+// -----------------------------------------------------------------------------
+
+// ElasticStore is the store for Elasticsearch and OpenSearch indices.
+//
+// Offset pagination uses from/size, which the server caps to 10,000 hits.
+// Cursor pagination uses search_after on a point in time, both encoded in an
+// opaque string cursor: use paging.StringModeCursor.
+type ElasticStore struct {
+	// KeepAlive is the point in time keep alive (defaults to DefaultKeepAlive)
+	KeepAlive string
+	// OpenSearch uses the OpenSearch point in time API
+	OpenSearch bool
+
+	ctx    context.Context
+	client *http.Client
+	url    string
+	index  string
+	query  map[string]interface{}
+	sort   []interface{}
+}
+
+// NewElasticStore returns a new Elasticsearch store instance, paginating
+// sources of the index hits matching query (all hits when nil). Requests are
+// sent with ctx.
+func NewElasticStore(ctx context.Context, client *http.Client, baseURL string, index string, query map[string]interface{}) (*ElasticStore, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if query == nil {
+		query = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+
+	return &ElasticStore{
+		KeepAlive: DefaultKeepAlive,
+		ctx:       ctx,
+		client:    client,
+		url:       strings.TrimSuffix(baseURL, "/"),
+		index:     index,
+		query:     query,
+	}, nil
+}
+
+// WithContext returns a new store running its queries with ctx instead of
+// the context it was created with.
+func (s *ElasticStore) WithContext(ctx context.Context) paging.Store {
+	store := *s
+	store.ctx = ctx
+	return &store
+}
+
+// Sort returns a new store ordered by fieldName.
+func (s *ElasticStore) Sort(fieldName string, reverse bool) (paging.Store, error) {
+	store := *s
+	store.sort = []interface{}{map[string]interface{}{fieldName: order(reverse)}}
+	return &store, nil
+}
+
+// Filter returns a new store restricted to hits matching all filters.
+func (s *ElasticStore) Filter(filters paging.Filters) (paging.Store, error) {
+	clauses := []interface{}{}
+	for _, filter := range filters {
+		clauses = append(clauses, filterClause(filter))
+	}
+
+	store := *s
+	store.query = map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   []interface{}{s.query},
+			"filter": clauses,
+		},
+	}
+	return &store, nil
+}
+
+// PaginateOffset paginates items with from/size and counts all hits.
+func (s *ElasticStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"query":            s.query,
+		"from":             offset,
+		"size":             limit,
+		"track_total_hits": true,
+	}
+	if s.sort != nil {
+		body["sort"] = s.sort
+	}
+
+	var res searchResponse
+	if err := s.do("POST", "/"+url.PathEscape(s.index)+"/_search", body, &res); err != nil {
+		return err
+	}
+
+	*count = res.Hits.Total.Value
+	return decode(items, slice, res.Hits.Hits)
+}
+
+// PaginateCursor paginates items with search_after on fieldName in a point
+// in time opened on the first page, and closed on the last one. A nil or
+// empty cursor starts from the first hit.
+func (s *ElasticStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	var nextCursor interface{}
+	return s.PaginateNextCursor(items, limit, cursor, fieldName, reverse, hasnext, &nextCursor)
+}
+
+// PaginateNextCursor is PaginateCursor also setting nextCursor to the
+// cursor of the last hit of the page, an empty string when there is none.
+func (s *ElasticStore) PaginateNextCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool, nextCursor *interface{}) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.cursor(cursor)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"query": s.query,
+		"size":  limit + 1,
+		"sort":  []interface{}{map[string]interface{}{fieldName: order(reverse)}},
+		"pit":   map[string]interface{}{"id": c.PIT, "keep_alive": s.KeepAlive},
+	}
+	if c.After != nil {
+		body["search_after"] = c.After
+	}
+
+	var res searchResponse
+	if err := s.do("POST", "/_search", body, &res); err != nil {
+		return err
+	}
+
+	pit := c.PIT
+	if res.PITID != "" {
+		pit = res.PITID
+	}
+
+	hits := res.Hits.Hits
+	*hasnext = int64(len(hits)) > limit
+	if *hasnext {
+		hits = hits[:limit]
+	}
+
+	*nextCursor = ""
+	if len(hits) > 0 {
+		*nextCursor = encodeCursor(elasticCursor{PIT: pit, After: hits[len(hits)-1].Sort})
+	}
+
+	if err := decode(items, slice, hits); err != nil {
+		return err
+	}
+
+	if !*hasnext {
+		return s.closePIT(pit)
+	}
+
+	return nil
+}
+
+// cursor decodes the cursor, or opens a point in time for the first page.
+func (s *ElasticStore) cursor(cursor interface{}) (elasticCursor, error) {
+	if cursor == nil || cursor == "" {
+		pit, err := s.openPIT()
+		return elasticCursor{PIT: pit}, err
+	}
+
+	raw, ok := cursor.(string)
+	if !ok {
+		return elasticCursor{}, errInvalidCursor
+	}
+
+	return decodeCursor(raw)
+}
+
+// openPIT opens a point in time on the index.
+func (s *ElasticStore) openPIT() (string, error) {
+	path := "/" + url.PathEscape(s.index) + "/_pit"
+	if s.OpenSearch {
+		path = "/" + url.PathEscape(s.index) + "/_search/point_in_time"
+	}
+
+	var res struct {
+		ID    string `json:"id"`
+		PITID string `json:"pit_id"`
+	}
+	if err := s.do("POST", path+"?keep_alive="+url.QueryEscape(s.KeepAlive), nil, &res); err != nil {
+		return "", err
+	}
+
+	if s.OpenSearch {
+		return res.PITID, nil
+	}
+	return res.ID, nil
+}
+
+// closePIT closes a point in time.
+func (s *ElasticStore) closePIT(pit string) error {
+	if s.OpenSearch {
+		return s.do("DELETE", "/_search/point_in_time", map[string]interface{}{"pit_id": []string{pit}}, nil)
+	}
+	return s.do("DELETE", "/_pit", map[string]interface{}{"id": pit}, nil)
+}
+
+// do sends a request and decodes the JSON response into res, when not nil.
+func (s *ElasticStore) do(method string, path string, body interface{}, res interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(s.ctx, method, s.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return responseError(resp)
+	}
+
+	if res == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// responseError returns the error of an error response, with its raw body
+// when it isn't an Elasticsearch error.
+func responseError(resp *http.Response) error {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("elasticsearch: %s: %w", resp.Status, err)
+	}
+
+	var e struct {
+		Error struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(b, &e); err != nil || e.Error.Type == "" {
+		return fmt.Errorf("elasticsearch: %s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+
+	return fmt.Errorf("elasticsearch: %s: %s: %s", resp.Status, e.Error.Type, e.Error.Reason)
+}
+
+// decode decodes the hits sources into items, pointing to slice.
+func decode(items interface{}, slice reflect.Value, hits []hit) error {
+	sources := make([]json.RawMessage, len(hits))
+	for i := range hits {
+		sources[i] = hits[i].Source
+	}
+
+	b, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+
+	slice.Set(reflect.Zero(slice.Type()))
+
+	return json.Unmarshal(b, items)
+}
+
+type searchResponse struct {
+	PITID string `json:"pit_id"`
+	Hits  struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []hit `json:"hits"`
+	} `json:"hits"`
+}
+
+type hit struct {
+	Source json.RawMessage `json:"_source"`
+	Sort   []interface{}   `json:"sort"`
+}
+
+// elasticCursor is the point in time and the sort values of the last hit.
+type elasticCursor struct {
+	PIT   string        `json:"pit"`
+	After []interface{} `json:"after,omitempty"`
+}
+
+func encodeCursor(c elasticCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(raw string) (elasticCursor, error) {
+	var c elasticCursor
+
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	// keep large sort values, like dates and _shard_doc, exact
+	d.UseNumber()
+	if err := d.Decode(&c); err != nil || c.PIT == "" {
+		return c, errInvalidCursor
+	}
+
+	return c, nil
+}
+
+func order(reverse bool) string {
+	if reverse {
+		return "desc"
+	}
+	return "asc"
+}
+
+// wildcardEscaper escapes the wildcards of wildcard queries.
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`)
+
+// filterClause returns the query clause of a filter.
+func filterClause(filter paging.Filter) map[string]interface{} {
+	field := filter.DBName
+
+	switch filter.Operator {
+	case paging.FilterNotEqual:
+		return map[string]interface{}{"bool": map[string]interface{}{
+			"must_not": map[string]interface{}{"term": map[string]interface{}{field: filter.Value}},
+		}}
+	case paging.FilterLessThan, paging.FilterLessThanOrEqual, paging.FilterGreaterThan, paging.FilterGreaterThanOrEqual:
+		return map[string]interface{}{"range": map[string]interface{}{field: map[string]interface{}{filter.Operator: filter.Value}}}
+	case paging.FilterIn:
+		return map[string]interface{}{"terms": map[string]interface{}{field: filter.Value}}
+	case paging.FilterLike:
+		pattern := wildcardEscaper.Replace(filter.Contains())
+		return map[string]interface{}{"wildcard": map[string]interface{}{field: "*" + pattern + "*"}}
+	}
+
+	return map[string]interface{}{"term": map[string]interface{}{field: filter.Value}}
+}
@@ -0,0 +1,331 @@
+package pagingelastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+type user struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Group int64  `json:"group"`
+}
+
+// nextCursor returns the cursor of the next page URI.
+func nextCursor(t *testing.T, p *paging.CursorPaginator) string {
+	values, err := url.ParseQuery(strings.TrimPrefix(p.NextURI.String, "?"))
+	assert.New(t).NoError(err)
+	return values.Get(paging.DefaultCursorKeyName)
+}
+
+// server is a stand-in for the search endpoints used by the store.
+type server struct {
+	*httptest.Server
+	// closed are the closed points in time
+	closed []string
+}
+
+// newServer returns a stand-in for the search endpoints used by the store,
+// supporting match_all and term queries sorted on a single numeric field.
+func newServer(t *testing.T) *server {
+	var docs []user
+	for i := int64(1); i <= 50; i++ {
+		docs = append(docs, user{ID: i, Name: fmt.Sprintf("user-%02d", i), Group: i % 2})
+	}
+
+	s := &server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE":
+			var body struct {
+				ID    string   `json:"id"`
+				PITID []string `json:"pit_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if r.URL.Path == "/_search/point_in_time" {
+				s.closed = append(s.closed, body.PITID...)
+			} else {
+				s.closed = append(s.closed, body.ID)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+			return
+		case r.URL.Path == "/broken/_search":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("upstream unavailable\n"))
+			return
+		case r.URL.Path == "/users/_pit":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "pit-1"})
+			return
+		case r.URL.Path == "/users/_search/point_in_time":
+			json.NewEncoder(w).Encode(map[string]interface{}{"pit_id": "pit-2"})
+			return
+		case r.URL.Path != "/users/_search" && r.URL.Path != "/_search":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"type": "index_not_found_exception", "reason": "no such index"}})
+			return
+		}
+
+		var body struct {
+			Query       map[string]interface{} `json:"query"`
+			From        int                    `json:"from"`
+			Size        int                    `json:"size"`
+			Sort        []map[string]string    `json:"sort"`
+			SearchAfter []float64              `json:"search_after"`
+			PIT         map[string]interface{} `json:"pit"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if r.URL.Path == "/_search" && body.PIT["id"] == nil {
+			t.Fatal("missing point in time")
+		}
+
+		var hits []user
+		for _, doc := range docs {
+			if matchQuery(doc, body.Query) {
+				hits = append(hits, doc)
+			}
+		}
+
+		field, desc := "id", false
+		for f, o := range firstSort(body.Sort) {
+			field, desc = f, o == "desc"
+		}
+		value := func(u user) float64 {
+			if field == "group" {
+				return float64(u.Group)
+			}
+			return float64(u.ID)
+		}
+		sort.SliceStable(hits, func(i, j int) bool {
+			if desc {
+				return value(hits[i]) > value(hits[j])
+			}
+			return value(hits[i]) < value(hits[j])
+		})
+		total := len(hits)
+
+		if body.SearchAfter != nil {
+			after := hits[:0:0]
+			for _, h := range hits {
+				if (!desc && value(h) > body.SearchAfter[0]) || (desc && value(h) < body.SearchAfter[0]) {
+					after = append(after, h)
+				}
+			}
+			hits = after
+		}
+		hits = hits[min(body.From, len(hits)):]
+		hits = hits[:min(body.Size, len(hits))]
+
+		results := []map[string]interface{}{}
+		for _, h := range hits {
+			results = append(results, map[string]interface{}{"_source": h, "sort": []float64{value(h)}})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"pit_id": body.PIT["id"],
+			"hits": map[string]interface{}{
+				"total": map[string]interface{}{"value": total},
+				"hits":  results,
+			},
+		})
+	}))
+
+	return s
+}
+
+func firstSort(sorts []map[string]string) map[string]string {
+	if len(sorts) == 0 {
+		return nil
+	}
+	return sorts[0]
+}
+
+func matchQuery(doc user, query map[string]interface{}) bool {
+	if b, ok := query["bool"].(map[string]interface{}); ok {
+		for _, key := range []string{"must", "filter"} {
+			for _, q := range b[key].([]interface{}) {
+				if !matchQuery(doc, q.(map[string]interface{})) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	if term, ok := query["term"].(map[string]interface{}); ok {
+		return term["group"].(float64) == float64(doc.Group)
+	}
+	return true
+}
+
+func TestElasticStore_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	server := newServer(t)
+	defer server.Close()
+
+	users := []user{}
+	store, err := NewElasticStore(context.Background(), server.Client(), server.URL, "users", map[string]interface{}{"term": map[string]interface{}{"group": 0}})
+	is.NoError(err)
+
+	sorted, err := store.Sort("id", true)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(sorted, paging.PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal(int64(25), paginator.Count)
+	is.Len(users, 10)
+	is.Equal(int64(30), users[0].ID)
+	is.Equal("?limit=10&offset=20", paginator.NextURI.String)
+}
+
+func TestElasticStore_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	server := newServer(t)
+	defer server.Close()
+
+	users := []user{}
+	store, err := NewElasticStore(context.Background(), server.Client(), server.URL, "users", nil)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.CursorOptions.Mode = paging.StringModeCursor
+
+	paginator, err := paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 20}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Len(users, 20)
+	is.Equal(int64(1), users[0].ID)
+	is.True(paginator.HasNext())
+
+	c, err := decodeCursor(nextCursor(t, paginator))
+	is.NoError(err)
+	is.Equal("pit-1", c.PIT)
+	is.Equal("20", fmt.Sprint(c.After[0]))
+
+	np, err := paginator.Next(&[]user{})
+	is.NoError(err)
+	is.Equal(int64(1), users[0].ID)
+	is.Equal(int64(21), pagingtest.PageItems[user](np)[0].ID)
+
+	np, err = np.Next(&[]user{})
+	is.NoError(err)
+	is.Len(pagingtest.PageItems[user](np), 10)
+	is.Equal(int64(41), pagingtest.PageItems[user](np)[0].ID)
+	is.False(np.HasNext())
+
+	// the point in time is closed on the last page
+	is.Equal([]string{"pit-1"}, server.closed)
+}
+
+func TestElasticStore_OpenSearch(t *testing.T) {
+	is := assert.New(t)
+
+	server := newServer(t)
+	defer server.Close()
+
+	users := []user{}
+	store, err := NewElasticStore(context.Background(), server.Client(), server.URL, "users", nil)
+	is.NoError(err)
+	store.OpenSearch = true
+
+	var (
+		hasnext bool
+		next    interface{}
+	)
+	is.NoError(store.PaginateNextCursor(&users, 10, nil, "id", true, &hasnext, &next))
+	is.True(hasnext)
+	is.Equal(int64(50), users[0].ID)
+
+	c, err := decodeCursor(next.(string))
+	is.NoError(err)
+	is.Equal("pit-2", c.PIT)
+	is.Empty(server.closed)
+
+	is.NoError(store.PaginateNextCursor(&users, 100, nil, "id", true, &hasnext, &next))
+	is.False(hasnext)
+	is.Equal([]string{"pit-2"}, server.closed)
+}
+
+func TestElasticStore_Filter(t *testing.T) {
+	is := assert.New(t)
+
+	server := newServer(t)
+	defer server.Close()
+
+	users := []user{}
+	store, err := NewElasticStore(context.Background(), server.Client(), server.URL, "users", nil)
+	is.NoError(err)
+
+	filtered, err := store.Filter(paging.Filters{{Name: "group", DBName: "group", Operator: paging.FilterEqual, Value: int64(1)}})
+	is.NoError(err)
+
+	var count int64
+	is.NoError(filtered.PaginateOffset(&users, 5, 0, &count))
+	is.Equal(int64(25), count)
+	is.Equal(int64(1), users[0].ID)
+	is.Equal(int64(3), users[1].ID)
+}
+
+func TestElasticStore_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	server := newServer(t)
+	defer server.Close()
+
+	users := []user{}
+	store, err := NewElasticStore(context.Background(), server.Client(), server.URL, "missing", nil)
+	is.NoError(err)
+
+	var count int64
+	err = store.PaginateOffset(&users, 10, 0, &count)
+	is.Error(err)
+	is.True(strings.Contains(err.Error(), "index_not_found_exception"))
+
+	var hasnext bool
+	is.True(errors.Is(store.PaginateCursor(&users, 10, "%%%", "id", false, &hasnext), paging.ErrInvalidCursor))
+	is.True(errors.Is(store.PaginateCursor(&users, 10, paging.NullCursor{}, "id", false, &hasnext), paging.ErrInvalidCursor))
+
+	// items must be a pointer to a slice
+	pagingtest.StoreErrors(t, store, "id")
+
+	// error responses which aren't JSON are kept as is
+	store, err = NewElasticStore(context.Background(), server.Client(), server.URL, "broken", nil)
+	is.NoError(err)
+	is.EqualError(store.PaginateOffset(&users, 10, 0, &count), "elasticsearch: 502 Bad Gateway: upstream unavailable")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	store, err = NewElasticStore(ctx, server.Client(), server.URL, "users", nil)
+	is.NoError(err)
+	is.True(errors.Is(store.PaginateOffset(&users, 10, 0, &count), context.Canceled))
+}
+
+func TestFilterClause(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(map[string]interface{}{"range": map[string]interface{}{"age": map[string]interface{}{"gte": 18}}},
+		filterClause(paging.Filter{DBName: "age", Operator: paging.FilterGreaterThanOrEqual, Value: 18}))
+	is.Equal(map[string]interface{}{"wildcard": map[string]interface{}{"name": "*jo*"}},
+		filterClause(paging.Filter{DBName: "name", Operator: paging.FilterLike, Value: "%jo%"}))
+	is.Equal(map[string]interface{}{"wildcard": map[string]interface{}{"name": `*j\*o\\%*`}},
+		filterClause(paging.Filter{DBName: "name", Operator: paging.FilterLike, Value: `%j*o\!%%`}))
+	is.Equal(map[string]interface{}{"terms": map[string]interface{}{"id": []int64{1, 2}}},
+		filterClause(paging.Filter{DBName: "id", Operator: paging.FilterIn, Value: []int64{1, 2}}))
+}
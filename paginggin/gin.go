@@ -0,0 +1,32 @@
+// Package paginggin binds paging to the gin web framework.
+package paginggin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ulule/paging"
+)
+
+// PageRequest returns the page request from the gin context query string.
+func PageRequest(c *gin.Context, options *paging.Options) (paging.PageRequest, error) {
+	return paging.PageRequestFromHTTP(c.Request, options)
+}
+
+// NewOffsetPaginator returns a new OffsetPaginator instance from the gin context.
+func NewOffsetPaginator(c *gin.Context, store paging.Store, options *paging.Options) (*paging.OffsetPaginator, error) {
+	return paging.NewOffsetPaginator(store, c.Request, options)
+}
+
+// NewCursorPaginator returns a new CursorPaginator instance from the gin context.
+func NewCursorPaginator(c *gin.Context, store paging.Store, options *paging.Options) (*paging.CursorPaginator, error) {
+	return paging.NewCursorPaginator(store, c.Request, options)
+}
+
+// Render sends the items and the paginator as JSON, with the next and
+// previous page links in the Link header.
+func Render(c *gin.Context, code int, paginator paging.Paginator, items interface{}) {
+	if link := paging.LinkHeader(paginator, c.Request.URL.Path); link != "" {
+		c.Header("Link", link)
+	}
+
+	c.JSON(code, paging.NewResponse(paginator, items))
+}
@@ -0,0 +1,46 @@
+package paginggin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+func TestRender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/numbers", func(c *gin.Context) {
+		var numbers []int
+		paginator, err := NewOffsetPaginator(c, pagingtest.NumberStore{}, nil)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		if err := paginator.Page(&numbers); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		Render(c, http.StatusOK, paginator, numbers)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, pagingtest.RenderRequest())
+
+	pagingtest.AssertRender(t, rec)
+}
+
+func TestPageRequest(t *testing.T) {
+	is := assert.New(t)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?limit=5&since=10", nil)
+	page, err := PageRequest(c, paging.NewOptions())
+	is.NoError(err)
+	is.Equal(int64(5), page.Limit)
+	is.Equal(int64(10), page.Cursor)
+}
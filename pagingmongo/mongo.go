@@ -0,0 +1,221 @@
+// Package pagingmongo provides a paging store for MongoDB collections.
+package pagingmongo
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"regexp"
+
+	"github.com/ulule/paging"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Collection is the part of *mongo.Collection used by the store.
+type Collection interface {
+	Find(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+}
+
+// -----------------------------------------------------------------------------
+// Mongo Store
+// -----------------------------------------------------------------------------
+
+// MongoStore is the store for MongoDB collections.
+type MongoStore struct {
+	ctx        context.Context
+	collection Collection
+	filter     interface{}
+	sort       bson.D
+	logger     *slog.Logger
+}
+
+// NewMongoStore returns a new MongoDB store instance, paginating documents
+// of the collection matching filter (all documents when nil).
+func NewMongoStore(ctx context.Context, collection Collection, filter interface{}) (*MongoStore, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	return &MongoStore{
+		ctx:        ctx,
+		collection: collection,
+		filter:     filter,
+	}, nil
+}
+
+// WithContext returns a new store running its queries with ctx instead of
+// the context it was created with.
+func (s *MongoStore) WithContext(ctx context.Context) paging.Store {
+	store := *s
+	store.ctx = ctx
+	return &store
+}
+
+// Sort returns a new store ordered by fieldName.
+func (s *MongoStore) Sort(fieldName string, reverse bool) (paging.Store, error) {
+	store := *s
+	store.sort = bson.D{{Key: fieldName, Value: direction(reverse)}}
+	return &store, nil
+}
+
+// Filter returns a new store restricted to documents matching all filters.
+func (s *MongoStore) Filter(filters paging.Filters) (paging.Store, error) {
+	conditions := bson.A{s.filter}
+	for _, filter := range filters {
+		conditions = append(conditions, filterCondition(filter))
+	}
+
+	store := *s
+	store.filter = bson.D{{Key: "$and", Value: conditions}}
+	return &store, nil
+}
+
+// WithLogger returns a new store logging the predicates of cursor pages at
+// debug level.
+func (s *MongoStore) WithLogger(logger *slog.Logger) *MongoStore {
+	store := *s
+	store.logger = logger
+	return &store
+}
+
+// PaginateOffset paginates items with skip and limit, and counts the
+// documents matching the filter.
+func (s *MongoStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSkip(offset).SetLimit(limit)
+	if s.sort != nil {
+		opts.SetSort(s.sort)
+	}
+
+	if err := s.find(items, slice, limit, opts); err != nil {
+		return err
+	}
+
+	total, err := s.collection.CountDocuments(s.ctx, s.filter)
+	if err != nil {
+		return err
+	}
+	*count = total
+
+	return nil
+}
+
+// PaginateCursor paginates items with $gt (or $lt when reverse is true) on
+// fieldName, which should be indexed. A string cursor on _id is converted to
+// an ObjectID when possible, a nil or empty string cursor starts from the
+// first document.
+func (s *MongoStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cursor.(paging.NullCursor); ok {
+		return paging.ErrNullCursorNotSupported
+	}
+
+	if s.sort != nil && (s.sort[0].Key != fieldName || s.sort[0].Value != direction(reverse)) {
+		return paging.ErrIncompatibleOrder
+	}
+
+	filter := s.filter
+	var predicate interface{}
+	if cursor != nil && cursor != "" {
+		operator := "$gt"
+		if reverse {
+			operator = "$lt"
+		}
+
+		predicate = bson.D{{Key: fieldName, Value: bson.D{{Key: operator, Value: cursorValue(fieldName, cursor)}}}}
+		filter = bson.D{{Key: "$and", Value: bson.A{s.filter, predicate}}}
+	}
+	paging.LogCursorPredicate(s.ctx, s.logger, predicate, nil, limit)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: fieldName, Value: direction(reverse)}}).
+		SetLimit(limit + 1)
+
+	cur, err := s.collection.Find(s.ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := cur.All(s.ctx, items); err != nil {
+		return err
+	}
+
+	if int64(slice.Len()) <= limit {
+		*hasnext = false
+		return nil
+	}
+
+	*hasnext = true
+	slice.Set(slice.Slice(0, int(limit)))
+	return nil
+}
+
+// find decodes the documents found into items, a zero limit returns no
+// document instead of all of them.
+func (s *MongoStore) find(items interface{}, slice reflect.Value, limit int64, opts *options.FindOptionsBuilder) error {
+	if limit == 0 {
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+		return nil
+	}
+
+	cur, err := s.collection.Find(s.ctx, s.filter, opts)
+	if err != nil {
+		return err
+	}
+
+	return cur.All(s.ctx, items)
+}
+
+func direction(reverse bool) int {
+	if reverse {
+		return -1
+	}
+	return 1
+}
+
+// cursorValue converts an hexadecimal string cursor on _id to an ObjectID.
+func cursorValue(fieldName string, cursor interface{}) interface{} {
+	if id, ok := cursor.(string); ok && fieldName == "_id" {
+		if oid, err := bson.ObjectIDFromHex(id); err == nil {
+			return oid
+		}
+	}
+	return cursor
+}
+
+// filterCondition returns the query condition of a filter.
+func filterCondition(filter paging.Filter) bson.D {
+	var value interface{}
+
+	switch filter.Operator {
+	case paging.FilterNotEqual:
+		value = bson.D{{Key: "$ne", Value: filter.Value}}
+	case paging.FilterLessThan:
+		value = bson.D{{Key: "$lt", Value: filter.Value}}
+	case paging.FilterLessThanOrEqual:
+		value = bson.D{{Key: "$lte", Value: filter.Value}}
+	case paging.FilterGreaterThan:
+		value = bson.D{{Key: "$gt", Value: filter.Value}}
+	case paging.FilterGreaterThanOrEqual:
+		value = bson.D{{Key: "$gte", Value: filter.Value}}
+	case paging.FilterIn:
+		value = bson.D{{Key: "$in", Value: filter.Value}}
+	case paging.FilterLike:
+		value = bson.D{{Key: "$regex", Value: regexp.QuoteMeta(filter.Contains())}}
+	default:
+		value = bson.D{{Key: "$eq", Value: filter.Value}}
+	}
+
+	return bson.D{{Key: filter.DBName, Value: value}}
+}
@@ -0,0 +1,268 @@
+package pagingmongo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// memoryCollection is an in-memory stand-in for a collection, supporting the
+// query operators used by the store.
+type memoryCollection struct {
+	docs []bson.M
+}
+
+func (c *memoryCollection) Find(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	var o options.FindOptions
+	for _, opt := range opts {
+		for _, set := range opt.List() {
+			if err := set(&o); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var docs []bson.M
+	for _, doc := range c.docs {
+		if match(doc, filter) {
+			docs = append(docs, doc)
+		}
+	}
+
+	if o.Sort != nil {
+		key := o.Sort.(bson.D)[0]
+		sort.SliceStable(docs, func(i, j int) bool {
+			if key.Value == -1 {
+				return compare(docs[j][key.Key], docs[i][key.Key]) < 0
+			}
+			return compare(docs[i][key.Key], docs[j][key.Key]) < 0
+		})
+	}
+
+	if o.Skip != nil {
+		docs = docs[min(int(*o.Skip), len(docs)):]
+	}
+	if o.Limit != nil && *o.Limit > 0 {
+		docs = docs[:min(int(*o.Limit), len(docs))]
+	}
+
+	documents := make([]interface{}, len(docs))
+	for i := range docs {
+		documents[i] = docs[i]
+	}
+
+	return mongo.NewCursorFromDocuments(documents, nil, nil)
+}
+
+func (c *memoryCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error) {
+	var count int64
+	for _, doc := range c.docs {
+		if match(doc, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func match(doc bson.M, filter interface{}) bool {
+	for _, e := range filter.(bson.D) {
+		if e.Key == "$and" {
+			for _, f := range e.Value.(bson.A) {
+				if !match(doc, f) {
+					return false
+				}
+			}
+			continue
+		}
+
+		for _, op := range e.Value.(bson.D) {
+			switch op.Key {
+			case "$eq":
+				if compare(doc[e.Key], op.Value) != 0 {
+					return false
+				}
+			case "$gt":
+				if compare(doc[e.Key], op.Value) <= 0 {
+					return false
+				}
+			case "$lt":
+				if compare(doc[e.Key], op.Value) >= 0 {
+					return false
+				}
+			case "$in":
+				found := false
+				for _, v := range op.Value.([]interface{}) {
+					found = found || compare(doc[e.Key], v) == 0
+				}
+				if !found {
+					return false
+				}
+			default:
+				panic("unsupported operator " + op.Key)
+			}
+		}
+	}
+	return true
+}
+
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		b := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		switch b := b.(string); {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	panic(fmt.Sprintf("unsupported type %T", a))
+}
+
+type user struct {
+	ID     int64  `bson:"_id"`
+	Name   string `bson:"name"`
+	Active bool   `bson:"active"`
+}
+
+func newCollection() *memoryCollection {
+	c := &memoryCollection{}
+	for i := int64(1); i <= 50; i++ {
+		c.docs = append(c.docs, bson.M{"_id": i, "name": fmt.Sprintf("user-%02d", i), "group": i % 2})
+	}
+	return c
+}
+
+func TestMongoStore_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewMongoStore(context.Background(), newCollection(), bson.D{{Key: "group", Value: bson.D{{Key: "$eq", Value: int64(0)}}}})
+	is.NoError(err)
+
+	sorted, err := store.Sort("name", true)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(sorted, paging.PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal(int64(25), paginator.Count)
+	is.Len(users, 10)
+	is.Equal("user-30", users[0].Name)
+	is.Equal("?limit=10&offset=20", paginator.NextURI.String)
+}
+
+func TestMongoStore_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewMongoStore(context.Background(), newCollection(), nil)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.CursorOptions.DBName = "_id"
+
+	paginator, err := paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 20}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Len(users, 20)
+	is.Equal(int64(1), users[0].ID)
+	is.Equal("?limit=20&since=20", paginator.NextURI.String)
+
+	np, err := paginator.Next(&[]user{})
+	is.NoError(err)
+	is.Equal(int64(1), users[0].ID)
+	is.Equal(int64(21), pagingtest.PageItems[user](np)[0].ID)
+
+	np, err = np.Next(&[]user{})
+	is.NoError(err)
+	is.Len(pagingtest.PageItems[user](np), 10)
+	is.False(np.HasNext())
+
+	options.CursorOptions.Reverse = true
+	paginator, err = paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 5, Cursor: int64(10)}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(9), users[0].ID)
+	is.Equal(int64(5), users[4].ID)
+	is.True(paginator.HasNext())
+}
+
+func TestMongoStore_Filter(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewMongoStore(context.Background(), newCollection(), nil)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.FilterSpec = paging.NewFilterSpec(paging.FilterField{Name: "id", DBName: "_id", Type: paging.FilterTypeInt, Operators: []string{paging.FilterIn, paging.FilterGreaterThan}})
+	page, err := paging.PageRequestFromValues(map[string][]string{"id": {"3,4,40"}, "id[gt]": {"3"}}, options)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(store, page, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(2), paginator.Count)
+	is.Equal([]int64{4, 40}, []int64{users[0].ID, users[1].ID})
+}
+
+func TestMongoStore_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewMongoStore(context.Background(), newCollection(), nil)
+	is.NoError(err)
+
+	var hasnext bool
+	is.Equal(paging.ErrNullCursorNotSupported, store.PaginateCursor(&users, 10, paging.NullCursor{}, "_id", false, &hasnext))
+
+	sorted, err := store.Sort("name", false)
+	is.NoError(err)
+	is.Equal(paging.ErrIncompatibleOrder, sorted.PaginateCursor(&users, 10, nil, "_id", false, &hasnext))
+
+	// items must be a pointer to a slice
+	pagingtest.StoreErrors(t, store, "_id")
+}
+
+func TestCursorValue(t *testing.T) {
+	is := assert.New(t)
+
+	oid := bson.NewObjectID()
+	is.Equal(oid, cursorValue("_id", oid.Hex()))
+	is.Equal("slug", cursorValue("_id", "slug"))
+	is.Equal(oid.Hex(), cursorValue("ref", oid.Hex()))
+}
+
+func TestMongoStore_Logger(t *testing.T) {
+	is := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	logger := pagingtest.DebugLogger(buf)
+
+	store, err := NewMongoStore(context.Background(), newCollection(), nil)
+	is.NoError(err)
+
+	var hasnext bool
+	is.NoError(store.WithLogger(logger).PaginateCursor(&[]user{}, 10, int64(42), "_id", true, &hasnext))
+	is.Contains(buf.String(), `msg="paging: cursor predicate" predicate="{\"_id\":{\"$lt\":{\"$numberLong\":\"42\"}}}" limit=10`)
+}
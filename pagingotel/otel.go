@@ -0,0 +1,76 @@
+// Package pagingotel provides an OpenTelemetry tracing hook for paginators.
+package pagingotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ulule/paging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the tracer.
+const TracerName = "github.com/ulule/paging/pagingotel"
+
+// -----------------------------------------------------------------------------
+// Tracer
+// -----------------------------------------------------------------------------
+
+// Tracer is a paging.Hook tracing paginator operations as spans named
+// "paging.<operation>", with their store calls as child spans.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a new Tracer instance, using the global tracer provider
+// when provider is nil.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &Tracer{
+		tracer: provider.Tracer(TracerName),
+	}
+}
+
+// Before starts the span of the operation.
+func (t *Tracer) Before(ctx context.Context, event *paging.HookEvent) context.Context {
+	attributes := []attribute.KeyValue{
+		attribute.String("paging.type", event.Type),
+		attribute.Int64("paging.limit", event.Limit),
+	}
+
+	if event.LimitClamped {
+		attributes = append(attributes, attribute.Bool("paging.limit_clamped", true))
+	}
+	if event.Type == paging.OffsetType {
+		attributes = append(attributes, attribute.Int64("paging.offset", event.Offset))
+	}
+	if event.Cursor != nil {
+		attributes = append(attributes, attribute.String("paging.cursor", fmt.Sprint(event.Cursor)))
+	}
+
+	ctx, _ = t.tracer.Start(ctx, "paging."+event.Operation, trace.WithAttributes(attributes...))
+	return ctx
+}
+
+// After ends the span of the operation, with its item count and error.
+func (t *Tracer) After(ctx context.Context, event *paging.HookEvent) {
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(attribute.Int("paging.items", event.Items))
+	if event.Type == paging.OffsetType {
+		span.SetAttributes(attribute.Int64("paging.count", event.Count))
+	}
+
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+
+	span.End()
+}
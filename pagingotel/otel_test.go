@@ -0,0 +1,80 @@
+package pagingotel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+var errLastPage = errors.New("last page failed")
+
+// lastPageStore is a NumberStore failing to paginate its last page.
+type lastPageStore struct {
+	pagingtest.NumberStore
+}
+
+func (s lastPageStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	if offset+limit >= 100 {
+		return errLastPage
+	}
+	return s.NumberStore.PaginateOffset(items, limit, offset, count)
+}
+
+func TestTracer(t *testing.T) {
+	is := assert.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	options := paging.NewOptions()
+	options.MaxLimit = 10
+	options.Hooks = []paging.Hook{NewTracer(provider)}
+
+	numbers := []int{}
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(lastPageStore{}, paging.PageRequest{Limit: 10, Offset: 80}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&numbers))
+
+	_, err = paginator.Next(&[]int{})
+	is.Equal(errLastPage, err)
+
+	spans := recorder.Ended()
+	if !is.Len(spans, 4) {
+		return
+	}
+
+	// store calls are children of the paginator operations
+	store, page, next := spans[0], spans[1], spans[3]
+	is.Equal("paging.paginate_offset", store.Name())
+	is.Equal("paging.page", page.Name())
+	is.Equal(page.SpanContext().SpanID(), store.Parent().SpanID())
+
+	values := attributes(page)
+	is.Equal("offset", values["paging.type"].AsString())
+	is.Equal(int64(10), values["paging.limit"].AsInt64())
+	is.Equal(int64(80), values["paging.offset"].AsInt64())
+	is.Equal(int64(10), values["paging.items"].AsInt64())
+	is.Equal(int64(100), values["paging.count"].AsInt64())
+	is.Equal(codes.Unset, page.Status().Code)
+
+	is.Equal("paging.next", next.Name())
+	is.Equal(codes.Error, next.Status().Code)
+	is.Equal(errLastPage.Error(), next.Status().Description)
+	is.Equal(next.SpanContext().SpanID(), spans[2].Parent().SpanID())
+	is.Len(next.Events(), 1)
+}
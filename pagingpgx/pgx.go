@@ -0,0 +1,265 @@
+// Package pagingpgx provides a paging store for pgx queries.
+package pagingpgx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ulule/paging"
+)
+
+// Querier is the part of *pgx.Conn, *pgxpool.Pool and pgx.Tx used by the
+// store.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// -----------------------------------------------------------------------------
+// PGX Store
+// -----------------------------------------------------------------------------
+
+// PGXStore is the store for pgx queries. The base query is wrapped in a
+// subquery, filtered, ordered and limited by the store.
+//
+// Rows are scanned into struct fields by db tag, or by case-insensitive
+// field name.
+type PGXStore struct {
+	ctx    context.Context
+	db     Querier
+	query  paging.SQLQuery
+	args   pgx.NamedArgs
+	logger *slog.Logger
+}
+
+// NewPGXStore returns a new pgx store instance, paginating the rows of
+// query. Named parameters of query (@name) are bound from args, names
+// starting with "paging_" are reserved to the store.
+func NewPGXStore(ctx context.Context, db Querier, query string, args pgx.NamedArgs) (*PGXStore, error) {
+	return &PGXStore{
+		ctx:   ctx,
+		db:    db,
+		query: paging.SQLQuery{Query: query, Named: true},
+		args:  copyArgs(args),
+	}, nil
+}
+
+// WithContext returns a new store running its queries with ctx instead of
+// the context it was created with.
+func (s *PGXStore) WithContext(ctx context.Context) paging.Store {
+	store := *s
+	store.ctx = ctx
+	return &store
+}
+
+// Sort returns a new store ordered by fieldName.
+func (s *PGXStore) Sort(fieldName string, reverse bool) (paging.Store, error) {
+	store := *s
+	store.query = s.query.Sort(fieldName, reverse)
+	return &store, nil
+}
+
+// Filter returns a new store restricted to rows matching all filters.
+func (s *PGXStore) Filter(filters paging.Filters) (paging.Store, error) {
+	store := *s
+	store.args = copyArgs(s.args)
+
+	for _, filter := range filters {
+		name := fmt.Sprintf("paging_filter_%d", len(store.query.Conditions))
+		store.query = store.query.Where(filterCondition(filter, name))
+		store.args[name] = filter.Value
+	}
+
+	return &store, nil
+}
+
+// DeferredJoin returns a new store paginating sorted offsets with a deferred
+// join on the unique keyName column, see paging.SQLQuery.DeferredKey.
+func (s *PGXStore) DeferredJoin(keyName string) *PGXStore {
+	store := *s
+	store.query.DeferredKey = keyName
+	return &store
+}
+
+// WithLogger returns a new store logging the predicates of cursor pages at
+// debug level.
+func (s *PGXStore) WithLogger(logger *slog.Logger) *PGXStore {
+	store := *s
+	store.logger = logger
+	return &store
+}
+
+// PaginateOffset paginates items with LIMIT and OFFSET, and counts the rows
+// of the query, sending both queries in a single batch.
+func (s *PGXStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	query, params := s.query.OffsetQuery(limit, offset)
+	countQuery, _ := s.query.CountQuery()
+
+	batch := &pgx.Batch{}
+	batch.Queue(query, s.namedArgs(params))
+	batch.Queue(countQuery, s.args)
+
+	results := s.db.SendBatch(s.ctx, batch)
+	defer results.Close()
+
+	rows, err := results.Query()
+	if err != nil {
+		return err
+	}
+
+	if err := scanRows(rows, slice); err != nil {
+		return err
+	}
+
+	if err := results.QueryRow().Scan(count); err != nil {
+		return err
+	}
+
+	return results.Close()
+}
+
+// PaginateCursor paginates items with fieldName > cursor (< cursor when
+// reverse is true), a nil or empty string cursor starts from the first row.
+// A store sorted on another order returns paging.ErrIncompatibleOrder.
+func (s *PGXStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	query, params, err := s.query.CursorQuery(limit+1, cursor, fieldName, reverse)
+	if err != nil {
+		return err
+	}
+
+	predicate, predicateArgs := s.query.CursorPredicate(cursor, fieldName, reverse)
+	paging.LogCursorPredicate(s.ctx, s.logger, predicate, predicateArgs, limit)
+
+	rows, err := s.db.Query(s.ctx, query, s.namedArgs(params))
+	if err != nil {
+		return err
+	}
+
+	if err := scanRows(rows, slice); err != nil {
+		return err
+	}
+
+	if int64(slice.Len()) <= limit {
+		*hasnext = false
+		return nil
+	}
+
+	*hasnext = true
+	slice.Set(slice.Slice(0, int(limit)))
+	return nil
+}
+
+// namedArgs returns the named arguments of the store with the page
+// parameters.
+func (s *PGXStore) namedArgs(params []interface{}) pgx.NamedArgs {
+	args := copyArgs(s.args)
+	for _, param := range params {
+		arg := param.(sql.NamedArg)
+		args[arg.Name] = arg.Value
+	}
+	return args
+}
+
+// filterCondition returns the condition of a filter bound to the name
+// parameter.
+func filterCondition(filter paging.Filter, name string) string {
+	if filter.Operator == paging.FilterIn {
+		return fmt.Sprintf("%s = ANY(@%s)", filter.DBName, name)
+	}
+
+	condition, _ := filter.SQL()
+	return strings.Replace(condition, "?", "@"+name, 1)
+}
+
+// scanRows scans rows into slice, a slice of structs or of pointers to
+// structs. Columns without a matching field are discarded.
+func scanRows(rows pgx.Rows, slice reflect.Value) error {
+	defer rows.Close()
+
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+
+	elemType := slice.Type().Elem()
+	ptr := elemType.Kind() == reflect.Ptr
+	if ptr {
+		elemType = elemType.Elem()
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+
+		var dest []interface{}
+		for _, column := range rows.FieldDescriptions() {
+			field := fieldByColumn(elem.Elem(), column.Name)
+			if !field.IsValid() {
+				dest = append(dest, new(interface{}))
+				continue
+			}
+			dest = append(dest, field.Addr().Interface())
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		if ptr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
+
+// fieldByColumn returns the struct field of a column, looking into embedded
+// structs.
+func fieldByColumn(v reflect.Value, column string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("db"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if f := fieldByColumn(v.Field(i), column); f.IsValid() {
+				return f
+			}
+			continue
+		}
+
+		if tag == column || (tag == "" && strings.EqualFold(strings.ReplaceAll(column, "_", ""), field.Name)) {
+			return v.Field(i)
+		}
+	}
+
+	return reflect.Value{}
+}
+
+func copyArgs(args pgx.NamedArgs) pgx.NamedArgs {
+	c := pgx.NamedArgs{}
+	for k, v := range args {
+		c[k] = v
+	}
+	return c
+}
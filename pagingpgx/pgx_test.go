@@ -0,0 +1,241 @@
+package pagingpgx
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+// fakeRows are rows of the user table columns.
+type fakeRows struct {
+	pgx.Rows
+	columns []string
+	values  [][]interface{}
+	current int
+}
+
+func (r *fakeRows) Close()     {}
+func (r *fakeRows) Err() error { return nil }
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, column := range r.columns {
+		fields[i].Name = column
+	}
+	return fields
+}
+
+func (r *fakeRows) Next() bool {
+	r.current++
+	return r.current <= len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	for i, value := range r.values[r.current-1] {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+type fakeRow struct {
+	value int64
+}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	*dest[0].(*int64) = r.value
+	return nil
+}
+
+type fakeBatchResults struct {
+	pgx.BatchResults
+	rows  *fakeRows
+	count int64
+}
+
+func (r *fakeBatchResults) Query() (pgx.Rows, error) { return r.rows, nil }
+func (r *fakeBatchResults) QueryRow() pgx.Row        { return fakeRow{r.count} }
+func (r *fakeBatchResults) Close() error             { return nil }
+
+// fakeQuerier returns n users, from the given id, to any query and records
+// the queries sent.
+type fakeQuerier struct {
+	from, n int64
+	queries []string
+	args    []pgx.NamedArgs
+}
+
+func (q *fakeQuerier) rows() *fakeRows {
+	rows := &fakeRows{columns: []string{"id", "user_name", "extra"}}
+	for id := q.from; id < q.from+q.n; id++ {
+		rows.values = append(rows.values, []interface{}{id, "user", true})
+	}
+	return rows
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q.queries = append(q.queries, sql)
+	q.args = append(q.args, args[0].(pgx.NamedArgs))
+	return q.rows(), nil
+}
+
+func (q *fakeQuerier) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	for _, query := range b.QueuedQueries {
+		q.queries = append(q.queries, query.SQL)
+		q.args = append(q.args, query.Arguments[0].(pgx.NamedArgs))
+	}
+	return &fakeBatchResults{rows: q.rows(), count: 50}
+}
+
+type base struct {
+	ID int64
+}
+
+type user struct {
+	base
+	Name   string `db:"user_name"`
+	Ignore string `db:"-"`
+}
+
+func TestPGXStore_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	db := &fakeQuerier{from: 11, n: 10}
+	users := []user{}
+	store, err := NewPGXStore(context.Background(), db, "SELECT * FROM users WHERE active = @active", pgx.NamedArgs{"active": true})
+	is.NoError(err)
+
+	sorted, err := store.Sort("id", false)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(sorted, paging.PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal([]string{
+		"SELECT * FROM (SELECT * FROM users WHERE active = @active) AS paging ORDER BY id ASC LIMIT @paging_limit OFFSET @paging_offset",
+		"SELECT COUNT(*) FROM (SELECT * FROM users WHERE active = @active) AS paging",
+	}, db.queries)
+	is.Equal(pgx.NamedArgs{"active": true, "paging_limit": int64(10), "paging_offset": int64(10)}, db.args[0])
+	is.Equal(pgx.NamedArgs{"active": true}, db.args[1])
+
+	is.Equal(int64(50), paginator.Count)
+	is.Len(users, 10)
+	is.Equal(user{base: base{ID: 11}, Name: "user"}, users[0])
+}
+
+func TestPGXStore_DeferredJoin(t *testing.T) {
+	is := assert.New(t)
+
+	db := &fakeQuerier{from: 11, n: 10}
+	users := []user{}
+	store, err := NewPGXStore(context.Background(), db, "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	sorted, err := store.DeferredJoin("id").Sort("name", true)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(sorted, paging.PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal("SELECT * FROM (SELECT * FROM users) AS paging WHERE id IN (SELECT * FROM ("+
+		"SELECT id FROM (SELECT * FROM users) AS paging ORDER BY name DESC, id LIMIT @paging_limit OFFSET @paging_offset"+
+		") AS paging_keys) ORDER BY name DESC, id", db.queries[0])
+	is.Equal(pgx.NamedArgs{"paging_limit": int64(10), "paging_offset": int64(10)}, db.args[0])
+	is.Len(users, 10)
+
+	// unsorted stores have no order to keep
+	db.queries = nil
+	is.NoError(store.DeferredJoin("id").PaginateOffset(&users, 10, 0, new(int64)))
+	is.Equal("SELECT * FROM (SELECT * FROM users) AS paging LIMIT @paging_limit OFFSET @paging_offset", db.queries[0])
+}
+
+func TestPGXStore_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	db := &fakeQuerier{from: 21, n: 21}
+	users := []user{}
+	store, err := NewPGXStore(context.Background(), db, "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.CursorOptions.Reverse = true
+	paginator, err := paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 20, Cursor: int64(42)}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal([]string{"SELECT * FROM (SELECT * FROM users) AS paging WHERE id < @paging_cursor ORDER BY id DESC LIMIT @paging_limit"}, db.queries)
+	is.Equal(pgx.NamedArgs{"paging_cursor": int64(42), "paging_limit": int64(21)}, db.args[0])
+	is.Len(users, 20)
+	is.Equal(int64(21), users[0].ID)
+	is.True(paginator.HasNext())
+}
+
+func TestPGXStore_Filter(t *testing.T) {
+	is := assert.New(t)
+
+	db := &fakeQuerier{}
+	users := []user{}
+	store, err := NewPGXStore(context.Background(), db, "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	filtered, err := store.Filter(paging.Filters{
+		{DBName: "id", Operator: paging.FilterIn, Value: []int64{1, 2}},
+		{DBName: "name", Operator: paging.FilterLike, Value: "%jo%"},
+	})
+	is.NoError(err)
+
+	var hasnext bool
+	is.NoError(filtered.PaginateCursor(&users, 10, nil, "id", false, &hasnext))
+	is.Equal("SELECT * FROM (SELECT * FROM users) AS paging WHERE id = ANY(@paging_filter_0) AND name LIKE @paging_filter_1 ESCAPE '!' ORDER BY id ASC LIMIT @paging_limit", db.queries[0])
+	is.Equal(pgx.NamedArgs{"paging_filter_0": []int64{1, 2}, "paging_filter_1": "%jo%", "paging_limit": int64(11)}, db.args[0])
+	is.Empty(users)
+	is.False(hasnext)
+}
+
+func TestPGXStore_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewPGXStore(context.Background(), &fakeQuerier{}, "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	var hasnext bool
+	is.Equal(paging.ErrNullCursorNotSupported, store.PaginateCursor(&users, 10, paging.NullCursor{}, "id", false, &hasnext))
+
+	sorted, err := store.Sort("name", false)
+	is.NoError(err)
+	is.Equal(paging.ErrIncompatibleOrder, sorted.PaginateCursor(&users, 10, nil, "id", false, &hasnext))
+
+	// items must be a pointer to a slice
+	pagingtest.StoreErrors(t, store, "id")
+}
+
+func TestScanRows(t *testing.T) {
+	is := assert.New(t)
+
+	users := []*user{{Name: "stale"}}
+	is.NoError(scanRows((&fakeQuerier{from: 1, n: 2}).rows(), reflect.ValueOf(&users).Elem()))
+	is.Equal([]*user{{base: base{ID: 1}, Name: "user"}, {base: base{ID: 2}, Name: "user"}}, users)
+}
+
+func TestPGXStore_Logger(t *testing.T) {
+	is := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	logger := pagingtest.DebugLogger(buf)
+
+	store, err := NewPGXStore(context.Background(), &fakeQuerier{from: 1, n: 10}, "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	var hasnext bool
+	is.NoError(store.WithLogger(logger).PaginateCursor(&[]user{}, 10, nil, "id", false, &hasnext))
+	is.Contains(buf.String(), `msg="paging: cursor predicate, from the first item" limit=10`)
+}
@@ -0,0 +1,109 @@
+// Package pagingprometheus provides a Prometheus metrics hook for
+// paginators.
+package pagingprometheus
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ulule/paging"
+)
+
+// Namespace is the namespace of the metrics.
+const Namespace = "paging"
+
+// -----------------------------------------------------------------------------
+// Metrics
+// -----------------------------------------------------------------------------
+
+// Metrics is a paging.Hook collecting Prometheus metrics:
+//
+//   - paging_operation_duration_seconds: the duration of paginator operations
+//     and store calls, by operation, type and status (the count latency of
+//     paging.CountObserver stores, GORM, SQL and sqlx, is its "count"
+//     operation)
+//   - paging_offset: the offset of offset pages, by operation, their depth
+//     distribution
+//   - paging_items: the number of items of pages, by type
+//   - paging_limit_clamped_total: the pages whose requested limit was
+//     restricted to the maximum limit, by type
+type Metrics struct {
+	duration *prometheus.HistogramVec
+	offset   *prometheus.HistogramVec
+	items    *prometheus.HistogramVec
+	clamped  *prometheus.CounterVec
+}
+
+// NewMetrics returns a new Metrics instance registered to registerer, the
+// default registerer when nil.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	m := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of paginator operations and store calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "type", "status"}),
+		offset: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "offset",
+			Help:      "Offset of offset pages.",
+			Buckets:   []float64{0, 10, 100, 1000, 10000, 100000, 1000000},
+		}, []string{"operation"}),
+		items: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "items",
+			Help:      "Number of items of pages.",
+			Buckets:   []float64{0, 1, 5, 10, 20, 50, 100, 200, 500, 1000},
+		}, []string{"type"}),
+		clamped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "limit_clamped_total",
+			Help:      "Pages whose requested limit was restricted to the maximum limit.",
+		}, []string{"type"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.duration, m.offset, m.items, m.clamped} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Before does nothing, the duration is measured by the paginator.
+func (m *Metrics) Before(ctx context.Context, event *paging.HookEvent) context.Context {
+	return ctx
+}
+
+// After observes the operation.
+func (m *Metrics) After(ctx context.Context, event *paging.HookEvent) {
+	status := "ok"
+	if event.Err != nil {
+		status = "error"
+	}
+	m.duration.WithLabelValues(event.Operation, event.Type, status).Observe(event.Duration.Seconds())
+
+	switch event.Operation {
+	case paging.OperationPage, paging.OperationNext, paging.OperationPrevious:
+	default:
+		return
+	}
+
+	if event.Type == paging.OffsetType {
+		m.offset.WithLabelValues(event.Operation).Observe(float64(event.Offset))
+	}
+
+	if event.LimitClamped {
+		m.clamped.WithLabelValues(event.Type).Inc()
+	}
+
+	if event.Err == nil {
+		m.items.WithLabelValues(event.Type).Observe(float64(event.Items))
+	}
+}
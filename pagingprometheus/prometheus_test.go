@@ -0,0 +1,69 @@
+package pagingprometheus
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+// sampleCount returns the number of observations of the histogram with the
+// given labels.
+func sampleCount(is *assert.Assertions, registry *prometheus.Registry, name string, labels map[string]string) uint64 {
+	families, err := registry.Gather()
+	is.NoError(err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	return 0
+}
+
+func TestMetrics(t *testing.T) {
+	is := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics(registry)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.MaxLimit = 10
+	options.MaxOffset = 85
+	options.Hooks = []paging.Hook{metrics}
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=50&offset=70", nil)
+	paginator, err := paging.NewOffsetPaginator(pagingtest.NumberStore{}, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&[]int{}))
+
+	_, err = paginator.Next(&[]int{})
+	is.NoError(err)
+
+	is.Equal(uint64(1), sampleCount(is, registry, "paging_operation_duration_seconds", map[string]string{"operation": "page", "type": "offset", "status": "ok"}))
+	is.Equal(uint64(1), sampleCount(is, registry, "paging_operation_duration_seconds", map[string]string{"operation": "next", "status": "ok"}))
+	is.Equal(uint64(2), sampleCount(is, registry, "paging_operation_duration_seconds", map[string]string{"operation": "paginate_offset"}))
+	is.Equal(uint64(1), sampleCount(is, registry, "paging_offset", map[string]string{"operation": "next"}))
+	is.Equal(uint64(2), sampleCount(is, registry, "paging_items", map[string]string{"type": "offset"}))
+	is.Equal(float64(2), testutil.ToFloat64(metrics.clamped.WithLabelValues("offset")))
+
+	// metrics are registered once
+	_, err = NewMetrics(registry)
+	is.Error(err)
+}
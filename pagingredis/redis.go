@@ -0,0 +1,311 @@
+// Package pagingredis provides a paging store for Redis sorted sets.
+package pagingredis
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ulule/paging"
+)
+
+// ScoreFieldName is the only field a sorted set can be sorted by.
+const ScoreFieldName = "score"
+
+// errInvalidCursor is returned by the RedisStore's PaginateCursor method
+// when the cursor isn't a "<score>:<member>" string.
+var errInvalidCursor = fmt.Errorf("%w: redis cursor", paging.ErrInvalidCursor)
+
+// Client is the part of redis.Cmdable used by the store.
+type Client interface {
+	ZCard(ctx context.Context, key string) *redis.IntCmd
+	ZCount(ctx context.Context, key, min, max string) *redis.IntCmd
+	ZScore(ctx context.Context, key, member string) *redis.FloatCmd
+	ZRank(ctx context.Context, key, member string) *redis.IntCmd
+	ZRevRank(ctx context.Context, key, member string) *redis.IntCmd
+	ZRangeArgs(ctx context.Context, z redis.ZRangeArgs) *redis.StringSliceCmd
+	ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd
+	ZRevRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd
+}
+
+// Loader hydrates items, a pointer to a slice, from sorted set members in
+// the same order.
+type Loader func(ctx context.Context, members []string, items interface{}) error
+
+// Members is a Loader copying members into items, a *[]string, it returns
+// paging.ErrInvalidItems for other items.
+func Members(ctx context.Context, members []string, items interface{}) error {
+	dest, ok := items.(*[]string)
+	if !ok || dest == nil {
+		return fmt.Errorf("%w: expected a *[]string, got %T", paging.ErrInvalidItems, items)
+	}
+
+	*dest = members
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Redis Store
+// -----------------------------------------------------------------------------
+
+// RedisStore is the store for Redis sorted sets, ordered by score then
+// member.
+//
+// Cursors are "<score>:<member>" strings of the last member of the page: use
+// paging.StringModeCursor. The cursor field name is ignored.
+type RedisStore struct {
+	ctx     context.Context
+	client  Client
+	key     string
+	loader  Loader
+	reverse bool
+}
+
+// NewRedisStore returns a new Redis store instance, paginating the members
+// of the sorted set at key, hydrated into items by loader.
+func NewRedisStore(ctx context.Context, client Client, key string, loader Loader) (*RedisStore, error) {
+	return &RedisStore{
+		ctx:    ctx,
+		client: client,
+		key:    key,
+		loader: loader,
+	}, nil
+}
+
+// WithContext returns a new store running its queries with ctx instead of
+// the context it was created with.
+func (s *RedisStore) WithContext(ctx context.Context) paging.Store {
+	store := *s
+	store.ctx = ctx
+	return &store
+}
+
+// Sort returns a new store ordered by score, highest first when reverse is
+// true. Sorting by any other field returns paging.ErrSortNotSupported.
+func (s *RedisStore) Sort(fieldName string, reverse bool) (paging.Store, error) {
+	if fieldName != ScoreFieldName {
+		return nil, paging.ErrSortNotSupported
+	}
+
+	store := *s
+	store.reverse = reverse
+	return &store, nil
+}
+
+// PaginateOffset paginates members with ZRANGE ... BYSCORE LIMIT and counts
+// them with ZCARD.
+func (s *RedisStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	if _, err := paging.ItemsSlice(items); err != nil {
+		return err
+	}
+
+	total, err := s.client.ZCard(s.ctx, s.key).Result()
+	if err != nil {
+		return err
+	}
+	*count = total
+
+	if limit == 0 {
+		return s.load(items, nil)
+	}
+
+	args := redis.ZRangeArgs{Key: s.key, Start: "-inf", Stop: "+inf", ByScore: true, Offset: offset, Count: limit}
+	if s.reverse {
+		args.Start, args.Stop, args.Rev = "+inf", "-inf", true
+	}
+
+	members, err := s.client.ZRangeArgs(s.ctx, args).Result()
+	if err != nil {
+		return err
+	}
+
+	return s.load(items, members)
+}
+
+// PaginateCursor paginates members after the cursor, highest scores first
+// when reverse is true. Members sharing the cursor score are fetched from
+// the one following the cursor member, the following ones with an exclusive
+// score bound. A nil or empty cursor starts from the first member.
+func (s *RedisStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	var nextCursor interface{}
+	return s.PaginateNextCursor(items, limit, cursor, fieldName, reverse, hasnext, &nextCursor)
+}
+
+// PaginateNextCursor is PaginateCursor also setting nextCursor to the
+// cursor of the last member of the page, an empty string when there is none.
+func (s *RedisStore) PaginateNextCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool, nextCursor *interface{}) error {
+	if _, err := paging.ItemsSlice(items); err != nil {
+		return err
+	}
+
+	var (
+		entries []redis.Z
+		bound   = "-inf"
+	)
+	if reverse {
+		bound = "+inf"
+	}
+
+	if cursor != nil && cursor != "" {
+		raw, ok := cursor.(string)
+		if !ok {
+			return errInvalidCursor
+		}
+
+		score, member, err := parseCursor(raw)
+		if err != nil {
+			return err
+		}
+
+		offset, err := s.tieOffset(reverse, score, member, limit+1)
+		if err != nil {
+			return err
+		}
+
+		if entries, err = s.rangeByScore(reverse, score, score, offset, limit+1); err != nil {
+			return err
+		}
+
+		bound = "(" + score
+	}
+
+	if int64(len(entries)) <= limit {
+		next, err := s.rangeByScore(reverse, bound, "", 0, limit+1-int64(len(entries)))
+		if err != nil {
+			return err
+		}
+		entries = append(entries, next...)
+	}
+
+	*hasnext = int64(len(entries)) > limit
+	if *hasnext {
+		entries = entries[:limit]
+	}
+
+	*nextCursor = ""
+	members := make([]string, len(entries))
+	for i, z := range entries {
+		members[i] = z.Member.(string)
+	}
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		*nextCursor = formatScore(last.Score) + ":" + last.Member.(string)
+	}
+
+	return s.load(items, members)
+}
+
+// tieOffset returns the number of members sharing the cursor score up to
+// the cursor member included, in the cursor order. When the member no longer
+// has the cursor score, the ties are scanned count by count to find it.
+func (s *RedisStore) tieOffset(reverse bool, score string, member string, count int64) (int64, error) {
+	value, err := strconv.ParseFloat(score, 64)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+
+	current, err := s.client.ZScore(s.ctx, s.key, member).Result()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	if err == nil && current == value {
+		rank, before, err := s.rank(reverse, score, member)
+		if err != nil {
+			return 0, err
+		}
+		return rank - before + 1, nil
+	}
+
+	var offset int64
+	for {
+		ties, err := s.rangeByScore(reverse, score, score, offset, count)
+		if err != nil {
+			return 0, err
+		}
+
+		for i, z := range ties {
+			if m := z.Member.(string); (!reverse && m > member) || (reverse && m < member) {
+				return offset + int64(i), nil
+			}
+		}
+
+		offset += int64(len(ties))
+		if int64(len(ties)) < count {
+			return offset, nil
+		}
+	}
+}
+
+// rank returns the rank of member in the cursor order, and the number of
+// members before the cursor score.
+func (s *RedisStore) rank(reverse bool, score string, member string) (int64, int64, error) {
+	if !reverse {
+		rank, err := s.client.ZRank(s.ctx, s.key, member).Result()
+		if err != nil {
+			return 0, 0, err
+		}
+		before, err := s.client.ZCount(s.ctx, s.key, "-inf", "("+score).Result()
+		return rank, before, err
+	}
+
+	rank, err := s.client.ZRevRank(s.ctx, s.key, member).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	before, err := s.client.ZCount(s.ctx, s.key, "("+score, "+inf").Result()
+	return rank, before, err
+}
+
+// rangeByScore returns count members from the offset one, from the from
+// score bound to the to one, unbounded when empty, in the cursor order.
+func (s *RedisStore) rangeByScore(reverse bool, from string, to string, offset int64, count int64) ([]redis.Z, error) {
+	if !reverse {
+		if to == "" {
+			to = "+inf"
+		}
+		return s.client.ZRangeByScoreWithScores(s.ctx, s.key, &redis.ZRangeBy{Min: from, Max: to, Offset: offset, Count: count}).Result()
+	}
+
+	if to == "" {
+		to = "-inf"
+	}
+	return s.client.ZRevRangeByScoreWithScores(s.ctx, s.key, &redis.ZRangeBy{Min: to, Max: from, Offset: offset, Count: count}).Result()
+}
+
+// load hydrates items, a pointer to a slice, from members, without calling
+// the loader when there is none.
+func (s *RedisStore) load(items interface{}, members []string) error {
+	if len(members) == 0 {
+		slice, err := paging.ItemsSlice(items)
+		if err != nil {
+			return err
+		}
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+		return nil
+	}
+
+	return s.loader(s.ctx, members, items)
+}
+
+// parseCursor returns the score and the member of a cursor.
+func parseCursor(cursor string) (string, string, error) {
+	idx := strings.Index(cursor, ":")
+	if idx < 0 {
+		return "", "", errInvalidCursor
+	}
+
+	score := cursor[:idx]
+	if _, err := strconv.ParseFloat(score, 64); err != nil {
+		return "", "", errInvalidCursor
+	}
+
+	return score, cursor[idx+1:], nil
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'g', -1, 64)
+}
@@ -0,0 +1,196 @@
+package pagingredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+type player struct {
+	Name string
+}
+
+func loadPlayers(ctx context.Context, members []string, items interface{}) error {
+	players := items.(*[]player)
+	*players = nil
+	for _, member := range members {
+		*players = append(*players, player{Name: member})
+	}
+	return nil
+}
+
+// newClient returns a client on a leaderboard of 30 players, scored by tens
+// so that players share their scores.
+func newClient(t *testing.T) *redis.Client {
+	server := miniredis.RunT(t)
+	for i := 1; i <= 30; i++ {
+		server.ZAdd("leaderboard", float64(i/10*10), fmt.Sprintf("player-%02d", i))
+	}
+	return redis.NewClient(&redis.Options{Addr: server.Addr()})
+}
+
+func TestRedisStore_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	players := []player{}
+	store, err := NewRedisStore(context.Background(), newClient(t), "leaderboard", loadPlayers)
+	is.NoError(err)
+
+	sorted, err := store.Sort(ScoreFieldName, true)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(sorted, paging.PageRequest{Limit: 5, Offset: 5}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&players))
+
+	is.Equal(int64(30), paginator.Count)
+	is.Len(players, 5)
+	is.Equal("player-25", players[0].Name)
+	is.Equal("?limit=5&offset=10", paginator.NextURI.String)
+
+	_, err = store.Sort("name", false)
+	is.Equal(paging.ErrSortNotSupported, err)
+}
+
+func TestRedisStore_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	members := []string{}
+	store, err := NewRedisStore(context.Background(), newClient(t), "leaderboard", Members)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.CursorOptions.Mode = paging.StringModeCursor
+
+	paginator, err := paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 7}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&members))
+	is.Equal([]string{"player-01", "player-02", "player-03", "player-04", "player-05", "player-06", "player-07"}, members)
+	is.Equal("?limit=7&since=0%3Aplayer-07", paginator.NextURI.String)
+
+	// the next page starts in the ties of the cursor score
+	np, err := paginator.Next(&[]string{})
+	is.NoError(err)
+	is.Equal([]string{"player-08", "player-09", "player-10", "player-11", "player-12", "player-13", "player-14"}, pagingtest.PageItems[string](np))
+
+	for np.HasNext() {
+		np, err = np.Next(&[]string{})
+		is.NoError(err)
+	}
+	is.Equal([]string{"player-29", "player-30"}, pagingtest.PageItems[string](np))
+
+	options.CursorOptions.Reverse = true
+	paginator, err = paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 3, Cursor: "10:player-12"}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&members))
+	is.Equal([]string{"player-11", "player-10", "player-09"}, members)
+	is.True(paginator.HasNext())
+}
+
+// countingClient counts the entries returned by the score range queries.
+type countingClient struct {
+	*redis.Client
+	entries int
+}
+
+func (c *countingClient) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd {
+	cmd := c.Client.ZRangeByScoreWithScores(ctx, key, opt)
+	c.entries += len(cmd.Val())
+	return cmd
+}
+
+func TestRedisStore_Ties(t *testing.T) {
+	is := assert.New(t)
+
+	server := miniredis.RunT(t)
+	for i := 1; i <= 1000; i++ {
+		server.ZAdd("leaderboard", 1, fmt.Sprintf("player-%04d", i))
+	}
+	client := &countingClient{Client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+
+	store, err := NewRedisStore(context.Background(), client, "leaderboard", Members)
+	is.NoError(err)
+
+	var hasnext bool
+	members := []string{}
+
+	// the ties are fetched from the cursor member, not loaded whole
+	is.NoError(store.PaginateCursor(&members, 3, "1:player-0500", ScoreFieldName, false, &hasnext))
+	is.Equal([]string{"player-0501", "player-0502", "player-0503"}, members)
+	is.True(hasnext)
+	is.Equal(4, client.entries)
+
+	// a removed cursor member falls back to comparing the ties
+	server.ZRem("leaderboard", "player-0500")
+	is.NoError(store.PaginateCursor(&members, 3, "1:player-0500", ScoreFieldName, false, &hasnext))
+	is.Equal([]string{"player-0501", "player-0502", "player-0503"}, members)
+
+	is.NoError(store.PaginateCursor(&members, 3, "1:player-0999", ScoreFieldName, false, &hasnext))
+	is.Equal([]string{"player-1000"}, members)
+	is.False(hasnext)
+}
+
+func TestRedisStore_Concurrent(t *testing.T) {
+	is := assert.New(t)
+
+	store, err := NewRedisStore(context.Background(), newClient(t), "leaderboard", Members)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.CursorOptions.Mode = paging.StringModeCursor
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(limit int64) {
+			defer wg.Done()
+
+			members := []string{}
+			paginator, err := paging.NewTokenPaginator(store, limit, "", options)
+			is.NoError(err)
+			is.NoError(paginator.Page(&members))
+			is.Len(members, int(limit))
+
+			// the next page token comes from this page, not another goroutine's
+			token, err := paginator.NextPageToken()
+			is.NoError(err)
+			next, err := paging.NewTokenPaginator(store, limit, token, options)
+			is.NoError(err)
+			is.NoError(next.Page(&members))
+			is.Equal(fmt.Sprintf("player-%02d", limit+1), members[0])
+		}(int64(i + 1))
+	}
+	wg.Wait()
+}
+
+func TestRedisStore_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	members := []string{}
+	store, err := NewRedisStore(context.Background(), newClient(t), "leaderboard", Members)
+	is.NoError(err)
+
+	var hasnext bool
+	is.True(errors.Is(store.PaginateCursor(&members, 10, "player-01", ScoreFieldName, false, &hasnext), paging.ErrInvalidCursor))
+	is.True(errors.Is(store.PaginateCursor(&members, 10, "ten:player-01", ScoreFieldName, false, &hasnext), paging.ErrInvalidCursor))
+	is.True(errors.Is(store.PaginateCursor(&members, 10, int64(10), ScoreFieldName, false, &hasnext), paging.ErrInvalidCursor))
+
+	var count int64
+	is.NoError(store.PaginateOffset(&members, 0, 0, &count))
+	is.Equal(int64(30), count)
+
+	// items must be a pointer to a slice
+	pagingtest.StoreErrors(t, store, ScoreFieldName)
+
+	// the Members loader only copies into a *[]string
+	is.True(errors.Is(store.PaginateCursor(&[]player{}, 10, nil, ScoreFieldName, false, &hasnext), paging.ErrInvalidItems))
+}
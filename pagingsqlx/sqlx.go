@@ -0,0 +1,166 @@
+// Package pagingsqlx provides a paging store for sqlx queries.
+package pagingsqlx
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ulule/paging"
+)
+
+// -----------------------------------------------------------------------------
+// SQLX Store
+// -----------------------------------------------------------------------------
+
+// SQLXStore is the store for sqlx queries. The base query is wrapped in a
+// subquery, filtered, ordered and limited by the store.
+type SQLXStore struct {
+	ctx          context.Context
+	db           *sqlx.DB
+	query        paging.SQLQuery
+	logger       *slog.Logger
+	observeCount paging.ObserveFunc
+}
+
+// NewSQLXStore returns a new sqlx store instance, paginating the rows of
+// query. Named parameters of query are bound from arg, a map or a struct,
+// when not nil.
+func NewSQLXStore(ctx context.Context, db *sqlx.DB, query string, arg interface{}) (*SQLXStore, error) {
+	var args []interface{}
+	if arg != nil {
+		var err error
+		query, args, err = sqlx.Named(query, arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &SQLXStore{
+		ctx:   ctx,
+		db:    db,
+		query: paging.SQLQuery{Query: query, Args: args},
+	}, nil
+}
+
+// WithContext returns a new store running its queries with ctx instead of
+// the context it was created with.
+func (s *SQLXStore) WithContext(ctx context.Context) paging.Store {
+	store := *s
+	store.ctx = ctx
+	return &store
+}
+
+// Sort returns a new store ordered by fieldName.
+func (s *SQLXStore) Sort(fieldName string, reverse bool) (paging.Store, error) {
+	store := *s
+	store.query = s.query.Sort(fieldName, reverse)
+	return &store, nil
+}
+
+// Filter returns a new store restricted to rows matching all filters.
+func (s *SQLXStore) Filter(filters paging.Filters) (paging.Store, error) {
+	store := *s
+	store.query = s.query.Filter(filters)
+	return &store, nil
+}
+
+// DeferredJoin returns a new store paginating sorted offsets with a deferred
+// join on the unique keyName column, see paging.SQLQuery.DeferredKey.
+func (s *SQLXStore) DeferredJoin(keyName string) *SQLXStore {
+	store := *s
+	store.query.DeferredKey = keyName
+	return &store
+}
+
+// WithLogger returns a new store logging the predicates of cursor pages at
+// debug level.
+func (s *SQLXStore) WithLogger(logger *slog.Logger) *SQLXStore {
+	store := *s
+	store.logger = logger
+	return &store
+}
+
+// PaginateOffset paginates items with LIMIT and OFFSET, and counts the rows
+// of the query.
+func (s *SQLXStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	if err := s.PaginateOffsetItems(items, limit, offset); err != nil {
+		return err
+	}
+
+	return s.observeCount.Run(func() error {
+		return s.Count(items, count)
+	})
+}
+
+// ObserveCount returns a new store running the counts of PaginateOffset
+// with observe.
+func (s *SQLXStore) ObserveCount(observe paging.ObserveFunc) paging.Store {
+	store := *s
+	store.observeCount = observe
+	return &store
+}
+
+// PaginateOffsetItems paginates items with LIMIT and OFFSET, without
+// counting them.
+func (s *SQLXStore) PaginateOffsetItems(items interface{}, limit, offset int64) error {
+	query, args := s.query.OffsetQuery(limit, offset)
+
+	return s.find(items, query, args...)
+}
+
+// Count counts the rows of the query.
+func (s *SQLXStore) Count(items interface{}, count *int64) error {
+	query, args := s.query.CountQuery()
+	q, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+
+	return s.db.GetContext(s.ctx, count, s.db.Rebind(q), args...)
+}
+
+// PaginateCursor paginates items with fieldName > cursor (< cursor when
+// reverse is true), a nil or empty string cursor starts from the first row.
+// A store sorted on another order returns paging.ErrIncompatibleOrder.
+func (s *SQLXStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	query, args, err := s.query.CursorQuery(limit+1, cursor, fieldName, reverse)
+	if err != nil {
+		return err
+	}
+
+	predicate, predicateArgs := s.query.CursorPredicate(cursor, fieldName, reverse)
+	paging.LogCursorPredicate(s.ctx, s.logger, predicate, predicateArgs, limit)
+
+	if err := s.find(items, query, args...); err != nil {
+		return err
+	}
+
+	slice := reflect.ValueOf(items).Elem()
+	if int64(slice.Len()) <= limit {
+		*hasnext = false
+		return nil
+	}
+
+	*hasnext = true
+	slice.Set(slice.Slice(0, int(limit)))
+	return nil
+}
+
+// find scans the rows of query into items, a pointer to a slice.
+func (s *SQLXStore) find(items interface{}, query string, args ...interface{}) error {
+	slice, err := paging.ItemsSlice(items)
+	if err != nil {
+		return err
+	}
+
+	q, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+
+	return s.db.SelectContext(s.ctx, items, s.db.Rebind(q), args...)
+}
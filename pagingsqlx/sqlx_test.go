@@ -0,0 +1,196 @@
+package pagingsqlx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+type user struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Group int64  `db:"grp"`
+}
+
+func newDB(t *testing.T) *sqlx.DB {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	db.MustExec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, grp INTEGER)")
+	for i := 1; i <= 50; i++ {
+		db.MustExec("INSERT INTO users (id, name, grp) VALUES (?, ?, ?)", i, fmt.Sprintf("user-%02d", i), i%2)
+	}
+	return db
+}
+
+func TestSQLXStore_OffsetPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewSQLXStore(context.Background(), newDB(t), "SELECT * FROM users WHERE grp = :grp", map[string]interface{}{"grp": 0})
+	is.NoError(err)
+
+	sorted, err := store.Sort("name", true)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(sorted, paging.PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal(int64(25), paginator.Count)
+	is.Len(users, 10)
+	is.Equal("user-30", users[0].Name)
+	is.Equal("?limit=10&offset=20", paginator.NextURI.String)
+}
+
+func TestSQLXStore_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewSQLXStore(context.Background(), newDB(t), "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	paginator, err := paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 20}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Len(users, 20)
+	is.Equal(int64(1), users[0].ID)
+	is.Equal("?limit=20&since=20", paginator.NextURI.String)
+
+	np, err := paginator.Next(&[]user{})
+	is.NoError(err)
+	is.Equal(int64(1), users[0].ID)
+	is.Equal(int64(21), pagingtest.PageItems[user](np)[0].ID)
+
+	np, err = np.Next(&[]user{})
+	is.NoError(err)
+	is.Len(pagingtest.PageItems[user](np), 10)
+	is.False(np.HasNext())
+
+	options := paging.NewOptions()
+	options.CursorOptions.Reverse = true
+	paginator, err = paging.NewCursorPaginatorFromPageRequest(store, paging.PageRequest{Limit: 5, Cursor: int64(10)}, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(9), users[0].ID)
+	is.Equal(int64(5), users[4].ID)
+	is.True(paginator.HasNext())
+}
+
+func TestSQLXStore_Filter(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewSQLXStore(context.Background(), newDB(t), "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	options := paging.NewOptions()
+	options.FilterSpec = paging.NewFilterSpec(paging.FilterField{Name: "id", Type: paging.FilterTypeInt, Operators: []string{paging.FilterIn, paging.FilterGreaterThan}})
+	page, err := paging.PageRequestFromValues(map[string][]string{"id": {"3,4,40"}, "id[gt]": {"3"}}, options)
+	is.NoError(err)
+
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(store, page, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(2), paginator.Count)
+	is.Equal([]int64{4, 40}, []int64{users[0].ID, users[1].ID})
+}
+
+func TestSQLXStore_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	users := []user{}
+	store, err := NewSQLXStore(context.Background(), newDB(t), "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	var hasnext bool
+	is.Equal(paging.ErrNullCursorNotSupported, store.PaginateCursor(&users, 10, paging.NullCursor{}, "id", false, &hasnext))
+
+	sorted, err := store.Sort("name", false)
+	is.NoError(err)
+	is.Equal(paging.ErrIncompatibleOrder, sorted.PaginateCursor(&users, 10, nil, "id", false, &hasnext))
+
+	// items must be a pointer to a slice
+	pagingtest.StoreErrors(t, store, "id")
+
+	_, err = NewSQLXStore(context.Background(), newDB(t), "SELECT * FROM users WHERE grp = :grp", map[string]interface{}{})
+	is.Error(err)
+}
+
+func TestSQLXStore_WithContext(t *testing.T) {
+	is := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	users := []user{}
+	store, err := NewSQLXStore(ctx, newDB(t), "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	var count int64
+	is.True(errors.Is(store.PaginateOffset(&users, 10, 0, &count), context.Canceled))
+	is.NoError(store.WithContext(context.Background()).PaginateOffset(&users, 10, 0, &count))
+
+	// the paginator context replaces the store one
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(store, paging.PageRequest{Limit: 10}, nil)
+	is.NoError(err)
+	paginator.Context = context.Background()
+	is.NoError(paginator.Page(&users))
+	is.Len(users, 10)
+}
+
+func TestSQLXStore_DeferredJoin(t *testing.T) {
+	is := assert.New(t)
+
+	db := newDB(t)
+	store, err := NewSQLXStore(context.Background(), db, "SELECT * FROM users WHERE grp = :grp", map[string]interface{}{"grp": 1})
+	is.NoError(err)
+
+	filtered, err := store.Filter(paging.Filters{{DBName: "id", Operator: paging.FilterGreaterThan, Value: 10}})
+	is.NoError(err)
+	plain, err := filtered.(*SQLXStore).Sort("name", true)
+	is.NoError(err)
+	deferred, err := filtered.(*SQLXStore).DeferredJoin("id").Sort("name", true)
+	is.NoError(err)
+
+	// the deferred join keeps the items and their order
+	for _, offset := range []int64{0, 5, 15} {
+		want, got := []user{}, []user{}
+		is.NoError(plain.(*SQLXStore).PaginateOffsetItems(&want, 5, offset))
+		is.NoError(deferred.(*SQLXStore).PaginateOffsetItems(&got, 5, offset))
+		is.Equal(want, got)
+	}
+
+	users := []user{}
+	paginator, err := paging.NewOffsetPaginatorFromPageRequest(deferred, paging.PageRequest{Limit: 10, Offset: 10}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(20), paginator.Count)
+	is.Len(users, 10)
+	is.Equal("user-29", users[0].Name)
+}
+
+func TestSQLXStore_Logger(t *testing.T) {
+	is := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	logger := pagingtest.DebugLogger(buf)
+
+	store, err := NewSQLXStore(context.Background(), newDB(t), "SELECT * FROM users", nil)
+	is.NoError(err)
+
+	var hasnext bool
+	is.NoError(store.WithLogger(logger).PaginateCursor(&[]user{}, 10, int64(42), "id", false, &hasnext))
+	is.Contains(buf.String(), `msg="paging: cursor predicate" predicate="id > ?" args=[42] limit=10`)
+}
@@ -0,0 +1,40 @@
+package paging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Response
+// -----------------------------------------------------------------------------
+
+// Response is a JSON response with the page items and the paginator.
+type Response struct {
+	Items interface{} `json:"items"`
+	Meta  Paginator   `json:"meta"`
+}
+
+// NewResponse returns a new Response instance.
+func NewResponse(paginator Paginator, items interface{}) Response {
+	return Response{
+		Items: items,
+		Meta:  paginator,
+	}
+}
+
+// LinkHeader returns the Link header value (RFC 8288) with the next and
+// previous page URIs relative to path, empty when there is no other page.
+func LinkHeader(paginator Paginator, path string) string {
+	var links []string
+
+	if uri := paginator.MakeNextURI(); uri.Valid {
+		links = append(links, fmt.Sprintf(`<%s%s>; rel="next"`, path, uri.String))
+	}
+
+	if uri := paginator.MakePreviousURI(); uri.Valid {
+		links = append(links, fmt.Sprintf(`<%s%s>; rel="prev"`, path, uri.String))
+	}
+
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,65 @@
+package paging
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkHeader(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	request, _ := http.NewRequest("GET", "http://example.com/users?limit=10&offset=10", nil)
+	paginator, err := NewOffsetPaginator(store, request, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal(`</users?limit=10&offset=20>; rel="next", </users?limit=10&offset=0>; rel="prev"`, LinkHeader(paginator, "/users"))
+
+	request, _ = http.NewRequest("GET", "http://example.com/users?limit=100", nil)
+	paginator, err = NewOffsetPaginator(store, request, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	is.Equal("", LinkHeader(paginator, "/users"))
+}
+
+func TestResponse_JSON(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	request, _ := http.NewRequest("GET", "http://example.com/users?limit=2", nil)
+	paginator, err := NewOffsetPaginator(store, request, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+
+	body, err := json.Marshal(NewResponse(paginator, users))
+	is.NoError(err)
+
+	var res struct {
+		Items []User                 `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	is.NoError(json.Unmarshal(body, &res))
+	is.Len(res.Items, 2)
+	is.Equal(map[string]interface{}{
+		"limit":       float64(2),
+		"offset":      float64(0),
+		"total_count": float64(100),
+		"next":        "?limit=2&offset=2",
+		"previous":    nil,
+	}, res.Meta)
+}
@@ -0,0 +1,171 @@
+package paging
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// SQL Query
+// -----------------------------------------------------------------------------
+
+// SQLQuery builds the queries of the stores paginating hand-written SQL,
+// SQLStore and the sqlx and pgx ones. The query is wrapped in a subquery,
+// then filtered, ordered and limited, so that counts match the rows returned.
+//
+// Page parameters are bound with ?, or with @paging_limit, @paging_offset
+// and @paging_cursor when Named is true, their arguments are then
+// sql.NamedArg values.
+type SQLQuery struct {
+	// Query is the wrapped query
+	Query string
+	// Args are the arguments of Query and Conditions
+	Args []interface{}
+	// Conditions are the conditions of the filters
+	Conditions []string
+	// Order is the ORDER BY clause of the sort
+	Order string
+	// DeferredKey is the unique key column of the deferred join of ordered
+	// offset pages: the offset only scans the key column, then the rows of
+	// its keys are fetched. The key breaks the ties of the order, so that
+	// both queries order the rows the same way.
+	DeferredKey string
+	// Named binds page parameters by name
+	Named bool
+}
+
+// Where returns the query restricted by condition and its args.
+func (q SQLQuery) Where(condition string, args ...interface{}) SQLQuery {
+	q.Conditions = append(append([]string{}, q.Conditions...), condition)
+	q.Args = append(append([]interface{}{}, q.Args...), args...)
+	return q
+}
+
+// Filter returns the query restricted to rows matching all filters.
+func (q SQLQuery) Filter(filters Filters) SQLQuery {
+	for _, filter := range filters {
+		condition, args := filter.SQL()
+		q = q.Where(condition, args...)
+	}
+	return q
+}
+
+// Sort returns the query ordered by fieldName.
+func (q SQLQuery) Sort(fieldName string, reverse bool) SQLQuery {
+	q.Order = fmt.Sprintf("%s %s", fieldName, cursorDirection(reverse))
+	return q
+}
+
+// OffsetQuery returns the query of an offset page and its arguments, with a
+// deferred join when the query is ordered and has a DeferredKey.
+func (q SQLQuery) OffsetQuery(limit, offset int64) (string, []interface{}) {
+	page := fmt.Sprintf(" LIMIT %s OFFSET %s", q.param("paging_limit"), q.param("paging_offset"))
+	params := q.params(sql.Named("paging_limit", limit), sql.Named("paging_offset", offset))
+
+	if q.DeferredKey == "" || q.Order == "" {
+		query, args := q.selectQuery(q.Order)
+		return query + page, append(args, params...)
+	}
+
+	order := q.Order
+	if column, _, _ := parseOrder(order); !sameColumn(column, q.DeferredKey) {
+		order += ", " + q.DeferredKey
+	}
+
+	keys := fmt.Sprintf("SELECT %s FROM (%s) AS paging%s ORDER BY %s%s", q.DeferredKey, q.Query, whereConditions(q.Conditions), order, page)
+
+	return q.Where(deferredCondition(q.DeferredKey, keys), append(append([]interface{}{}, q.Args...), params...)...).selectQuery(order)
+}
+
+// deferredCondition returns the condition of a deferred join, restricting
+// keyName to the keys selected by the keys query.
+func deferredCondition(keyName string, keys string) string {
+	// MySQL doesn't support LIMIT in IN subqueries, only in derived tables
+	return fmt.Sprintf("%s IN (SELECT * FROM (%s) AS paging_keys)", keyName, keys)
+}
+
+// CursorQuery returns the query of a cursor page, limit rows with fieldName
+// > cursor (< cursor when reverse is true), and its arguments. A nil or empty
+// string cursor starts from the first row.
+//
+// It returns ErrNullCursorNotSupported for a NullCursor, and
+// ErrIncompatibleOrder when the query is ordered on another field.
+func (q SQLQuery) CursorQuery(limit int64, cursor interface{}, fieldName string, reverse bool) (string, []interface{}, error) {
+	if _, ok := cursor.(NullCursor); ok {
+		return "", nil, ErrNullCursorNotSupported
+	}
+
+	order := fmt.Sprintf("%s %s", fieldName, cursorDirection(reverse))
+	if q.Order != "" && q.Order != order {
+		return "", nil, ErrIncompatibleOrder
+	}
+
+	if predicate, args := q.CursorPredicate(cursor, fieldName, reverse); predicate != "" {
+		q = q.Where(predicate, args...)
+	}
+
+	query, args := q.selectQuery(order)
+
+	return query + " LIMIT " + q.param("paging_limit"), append(args, q.params(sql.Named("paging_limit", limit))...), nil
+}
+
+// CursorPredicate returns the condition of a cursor and its arguments, an
+// empty condition for a nil or empty string cursor.
+func (q SQLQuery) CursorPredicate(cursor interface{}, fieldName string, reverse bool) (string, []interface{}) {
+	if cursor == nil || cursor == "" {
+		return "", nil
+	}
+
+	operator := ">"
+	if reverse {
+		operator = "<"
+	}
+
+	return fmt.Sprintf("%s %s %s", fieldName, operator, q.param("paging_cursor")), q.params(sql.Named("paging_cursor", cursor))
+}
+
+// CountQuery returns the query counting the filtered rows and its
+// arguments.
+func (q SQLQuery) CountQuery() (string, []interface{}) {
+	return fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS paging%s", q.Query, whereConditions(q.Conditions)), q.Args
+}
+
+// selectQuery returns the filtered query with order and its arguments.
+func (q SQLQuery) selectQuery(order string) (string, []interface{}) {
+	query := fmt.Sprintf("SELECT * FROM (%s) AS paging%s", q.Query, whereConditions(q.Conditions))
+	if order != "" {
+		query += " ORDER BY " + order
+	}
+
+	return query, append([]interface{}{}, q.Args...)
+}
+
+// param returns the bind variable of a page parameter.
+func (q SQLQuery) param(name string) string {
+	if q.Named {
+		return "@" + name
+	}
+	return "?"
+}
+
+// params returns the arguments of page parameters.
+func (q SQLQuery) params(args ...sql.NamedArg) []interface{} {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		if q.Named {
+			params[i] = arg
+		} else {
+			params[i] = arg.Value
+		}
+	}
+	return params
+}
+
+// whereConditions returns the WHERE clause of conditions.
+func whereConditions(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
@@ -0,0 +1,61 @@
+package paging
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLQuery(t *testing.T) {
+	is := assert.New(t)
+
+	q := SQLQuery{Query: "SELECT * FROM users WHERE number > ?", Args: []interface{}{5}}
+	q.DeferredKey = "id"
+	filtered := q.Filter(Filters{{DBName: "number", Operator: FilterLessThanOrEqual, Value: 90}})
+	is.Empty(q.Conditions)
+
+	query, args := filtered.OffsetQuery(10, 20)
+	is.Equal("SELECT * FROM (SELECT * FROM users WHERE number > ?) AS paging WHERE number <= ? LIMIT ? OFFSET ?", query)
+	is.Equal([]interface{}{5, 90, int64(10), int64(20)}, args)
+
+	// the deferred key breaks the ties of the order
+	query, _ = filtered.Sort("name", false).OffsetQuery(10, 20)
+	is.Contains(query, "ORDER BY name ASC, id LIMIT ? OFFSET ?) AS paging_keys) ORDER BY name ASC, id")
+	query, _ = filtered.Sort("id", true).OffsetQuery(10, 20)
+	is.Contains(query, "ORDER BY id DESC LIMIT ? OFFSET ?) AS paging_keys) ORDER BY id DESC")
+
+	query, args = filtered.CountQuery()
+	is.Equal("SELECT COUNT(*) FROM (SELECT * FROM users WHERE number > ?) AS paging WHERE number <= ?", query)
+	is.Equal([]interface{}{5, 90}, args)
+
+	query, args, err := filtered.CursorQuery(11, 42, "id", true)
+	is.NoError(err)
+	is.Equal("SELECT * FROM (SELECT * FROM users WHERE number > ?) AS paging WHERE number <= ? AND id < ? ORDER BY id DESC LIMIT ?", query)
+	is.Equal([]interface{}{5, 90, 42, int64(11)}, args)
+
+	query, _, err = filtered.CursorQuery(11, nil, "id", false)
+	is.NoError(err)
+	is.Equal("SELECT * FROM (SELECT * FROM users WHERE number > ?) AS paging WHERE number <= ? ORDER BY id ASC LIMIT ?", query)
+
+	_, _, err = filtered.CursorQuery(11, NullCursor{}, "id", false)
+	is.Equal(ErrNullCursorNotSupported, err)
+
+	_, _, err = filtered.Sort("name", false).CursorQuery(11, 42, "id", false)
+	is.Equal(ErrIncompatibleOrder, err)
+}
+
+func TestSQLQuery_Named(t *testing.T) {
+	is := assert.New(t)
+
+	q := SQLQuery{Query: "SELECT * FROM users", Named: true}
+
+	query, args, err := q.CursorQuery(11, 42, "id", false)
+	is.NoError(err)
+	is.Equal("SELECT * FROM (SELECT * FROM users) AS paging WHERE id > @paging_cursor ORDER BY id ASC LIMIT @paging_limit", query)
+	is.Equal([]interface{}{sql.Named("paging_cursor", 42), sql.Named("paging_limit", int64(11))}, args)
+
+	query, args = q.Sort("name", true).OffsetQuery(10, 20)
+	is.Equal("SELECT * FROM (SELECT * FROM users) AS paging ORDER BY name DESC LIMIT @paging_limit OFFSET @paging_offset", query)
+	is.Equal([]interface{}{sql.Named("paging_limit", int64(10)), sql.Named("paging_offset", int64(20))}, args)
+}
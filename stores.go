@@ -1,20 +1,109 @@
 package paging
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/jinzhu/gorm"
 )
 
+// ErrIncompatibleOrder is returned by the GORMStore's PaginateCursor method
+// when the query is already ordered in a way that doesn't match the cursor.
+var ErrIncompatibleOrder = errors.New("query order is incompatible with cursor")
+
+// ErrNullCursorNotSupported is returned by the PaginateCursor method of
+// stores which don't paginate NullCursor cursors.
+var ErrNullCursorNotSupported = errors.New("store does not support null cursors")
+
 // -----------------------------------------------------------------------------
 // Interfaces
 // -----------------------------------------------------------------------------
 
-// Store is a store.
+// Store is a store, an immutable query description: items are paginated
+// into the destination given to each call, a pointer to a slice, so that a
+// store can be shared by concurrent paginators.
 type Store interface {
-	PaginateOffset(limit, offset int64, count *int64) error
-	PaginateCursor(limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error
-	GetItems() interface{}
+	PaginateOffset(items interface{}, limit, offset int64, count *int64) error
+	PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error
+}
+
+// Seeker is a store which can paginate around a cursor value.
+type Seeker interface {
+	// PaginateSeek paginates items from the cursor value included, or the
+	// items preceding it when before is true, in the cursor order. A nil
+	// cursor is unbounded.
+	PaginateSeek(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, before bool, hasmore *bool) error
+}
+
+// NextCursorer is a store which makes the cursor of the next page itself,
+// like search engines' opaque cursors, instead of the paginator reading it
+// from the last item.
+type NextCursorer interface {
+	// PaginateNextCursor is PaginateCursor also setting nextCursor to the
+	// cursor of the page following the items.
+	PaginateNextCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool, nextCursor *interface{}) error
+}
+
+// Counter is a store which can paginate with offset and count its items
+// apart.
+type Counter interface {
+	// PaginateOffsetItems paginates items with limit and offset, without
+	// counting them.
+	PaginateOffsetItems(items interface{}, limit, offset int64) error
+	// Count counts the items of the store, items is a pointer to a slice
+	// of their type.
+	Count(items interface{}, count *int64) error
+}
+
+// Contexter is a store running its queries with a context. The paginators
+// give it their Context, or their request context, when set.
+type Contexter interface {
+	// WithContext returns a new store running its queries with ctx.
+	WithContext(ctx context.Context) Store
+}
+
+// CountObserver is a store reporting the count of PaginateOffset, so that
+// hooks observe its latency apart.
+type CountObserver interface {
+	// ObserveCount returns a new store running the counts of PaginateOffset
+	// with observe.
+	ObserveCount(observe ObserveFunc) Store
+}
+
+// ObserveFunc runs and observes a store call.
+type ObserveFunc func(call func() error) error
+
+// Run runs call with f, or directly when f is nil.
+func (f ObserveFunc) Run(call func() error) error {
+	if f == nil {
+		return call()
+	}
+	return f(call)
+}
+
+// Sorter is a store which can be sorted.
+type Sorter interface {
+	// Sort returns a new store ordered by fieldName, DESC when reverse is
+	// true, instead of its current order.
+	Sort(fieldName string, reverse bool) (Store, error)
+}
+
+// NullCursor is the cursor of a nullable column, rows with a NULL value are
+// ordered first or last and by the unique Key column between them.
+//
+// A NullCursor with neither Value nor Key starts from the first row.
+type NullCursor struct {
+	// Value is the cursor value, nil when the cursor is in the NULL rows
+	Value interface{}
+	// Key is the key of the last row when the cursor is in the NULL rows
+	Key interface{}
+	// KeyDBName is the key database column name
+	KeyDBName string
+	// Nulls orders NULL values first or last (NullsFirst or NullsLast)
+	Nulls string
 }
 
 // -----------------------------------------------------------------------------
@@ -23,64 +112,534 @@ type Store interface {
 
 // GORMStore is the store for GORM ORM.
 type GORMStore struct {
-	db    *gorm.DB
-	items interface{}
+	db *gorm.DB
+	// ctx is the context of the logs
+	ctx context.Context
+	// deferredKey is the key column of deferred join offset pages
+	deferredKey string
+	// logger logs the cursor predicates
+	logger *slog.Logger
+	// observeCount observes the counts
+	observeCount ObserveFunc
 }
 
 // NewGORMStore returns a new GORM store instance.
-func NewGORMStore(db *gorm.DB, items interface{}) (*GORMStore, error) {
+func NewGORMStore(db *gorm.DB) (*GORMStore, error) {
 	return &GORMStore{
-		db:    db,
-		items: items,
+		db:  db,
+		ctx: context.Background(),
 	}, nil
 }
 
-// GetItems return the current result
-func (s *GORMStore) GetItems() interface{} {
-	return s.items
+// WithContext returns a new store logging with ctx, GORM v1 queries don't
+// take a context.
+func (s *GORMStore) WithContext(ctx context.Context) Store {
+	store := *s
+	store.ctx = ctx
+	return &store
 }
 
-// PaginateOffset paginates items from the store and update page instance.
-func (s *GORMStore) PaginateOffset(limit, offset int64, count *int64) error {
+// Filter returns a new store restricted to items matching all filters.
+func (s *GORMStore) Filter(filters Filters) (Store, error) {
 	q := s.db
-	q = q.Limit(int(limit))
-	q = q.Offset(int(offset))
-	q = q.Find(s.items)
-	q = q.Limit(-1)
-	q = q.Offset(-1)
+	for _, filter := range filters {
+		query, args := filter.SQL()
+		q = q.Where(query, args...)
+	}
 
-	if err := q.Count(count).Error; err != nil {
+	store := *s
+	store.db = q
+	return &store, nil
+}
+
+// Sort returns a new store ordered by fieldName instead of its current order.
+func (s *GORMStore) Sort(fieldName string, reverse bool) (Store, error) {
+	store := *s
+	store.db = s.db.Order(fmt.Sprintf("%s %s", fieldName, cursorDirection(reverse)), true)
+	return &store, nil
+}
+
+// DeferredJoin returns a new store paginating ordered offsets with a
+// deferred join on the unique keyName column, like SQLQuery.DeferredKey.
+func (s *GORMStore) DeferredJoin(keyName string) *GORMStore {
+	store := *s
+	store.deferredKey = keyName
+	return &store
+}
+
+// WithLogger returns a new store logging the predicates of cursor pages at
+// debug level.
+func (s *GORMStore) WithLogger(logger *slog.Logger) *GORMStore {
+	store := *s
+	store.logger = logger
+	return &store
+}
+
+// PaginateOffset paginates items from the store and update page instance.
+func (s *GORMStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	if err := s.PaginateOffsetItems(items, limit, offset); err != nil {
 		return err
 	}
 
-	return nil
+	return s.observeCount.Run(func() error {
+		return s.Count(items, count)
+	})
 }
 
-// PaginateCursor paginates items from the store and update page instance for cursor pagination system.
-// cursor can be an ID or a date (time.Time)
-func (s *GORMStore) PaginateCursor(limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+// ObserveCount returns a new store running the counts of PaginateOffset
+// with observe.
+func (s *GORMStore) ObserveCount(observe ObserveFunc) Store {
+	store := *s
+	store.observeCount = observe
+	return &store
+}
+
+// PaginateOffsetItems paginates items with limit and offset, without
+// counting them.
+func (s *GORMStore) PaginateOffsetItems(items interface{}, limit, offset int64) error {
+	if _, err := ItemsSlice(items); err != nil {
+		return err
+	}
+
+	orders := getOrders(s.db, items)
+	if s.deferredKey == "" || len(orders) == 0 {
+		return s.db.Limit(int(limit)).Offset(int(offset)).Find(items).Error
+	}
+
 	q := s.db
+	if column, _, _ := parseOrder(orders[0]); !sameColumn(column, s.deferredKey) {
+		q = q.Order(s.deferredKey)
+	}
+
+	keys := q.Model(items).Select(s.deferredKey).Limit(int(limit)).Offset(int(offset))
+
+	return q.Where(deferredCondition(s.deferredKey, "?"), keys.QueryExpr()).Find(items).Error
+}
+
+// Count counts the rows of the store query without its order in a
+// subquery, so that the count of grouped, distinct and joined queries
+// matches the rows returned. Preloads aren't run.
+func (s *GORMStore) Count(items interface{}, count *int64) error {
+	expr := s.db.Limit(-1).Offset(-1).Order(nil, true).Model(items).QueryExpr()
 
-	q = q.Limit(limit + 1)
+	return s.db.New().Raw("SELECT COUNT(*) FROM (?) AS paging", expr).Row().Scan(count)
+}
+
+// PaginateCursor paginates items from the store and update page instance for cursor pagination system.
+// cursor can be an ID, a date (time.Time) or a string, a nil or empty string
+// cursor starts from the first item.
+//
+// The query is ordered by fieldName (DESC when reverse is true) unless it is
+// already ordered by it in the same direction, any other leading order
+// returns ErrIncompatibleOrder.
+//
+// A NullCursor also paginates rows with a NULL value, the query order must
+// then be the cursor order with the same NULLS FIRST or NULLS LAST.
+func (s *GORMStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	if _, err := ItemsSlice(items); err != nil {
+		return err
+	}
 
+	if nc, ok := cursor.(NullCursor); ok {
+		return s.paginateNullCursor(items, limit, nc, fieldName, reverse, hasnext)
+	}
+
+	q, err := s.orderByCursor(items, fieldName, reverse, "", "")
+	if err != nil {
+		return err
+	}
+
+	var predicate string
+	switch {
+	case cursor == nil, cursor == "":
+	case reverse:
+		predicate = fmt.Sprintf("%s < ?", fieldName)
+	default:
+		predicate = fmt.Sprintf("%s > ?", fieldName)
+	}
+
+	if predicate != "" {
+		q = q.Where(predicate, cursor)
+	}
+	LogCursorPredicate(s.ctx, s.logger, predicate, []interface{}{cursor}, limit)
+
+	return findCursor(q, items, limit, hasnext)
+}
+
+// paginateNullCursor paginates items from a cursor on a nullable column.
+func (s *GORMStore) paginateNullCursor(items interface{}, limit int64, cursor NullCursor, fieldName string, reverse bool, hasnext *bool) error {
+	q, err := s.orderByCursor(items, fieldName, reverse, cursor.Nulls, cursor.KeyDBName)
+	if err != nil {
+		return err
+	}
+
+	operator := ">"
 	if reverse {
-		q = q.Where(fmt.Sprintf("%s < ?", fieldName), cursor)
-	} else {
+		operator = "<"
+	}
+
+	var predicate string
+	switch {
+	case cursor.Value != nil && cursor.Nulls == NullsLast:
+		predicate = fmt.Sprintf("(%s %s ? OR %s IS NULL)", fieldName, operator, fieldName)
+	case cursor.Value != nil:
+		predicate = fmt.Sprintf("%s %s ?", fieldName, operator)
+	case cursor.Key != nil && cursor.Nulls == NullsLast:
+		predicate = fmt.Sprintf("%s IS NULL AND %s %s ?", fieldName, cursor.KeyDBName, operator)
+	case cursor.Key != nil:
+		predicate = fmt.Sprintf("((%s IS NULL AND %s %s ?) OR %s IS NOT NULL)", fieldName, cursor.KeyDBName, operator, fieldName)
+	}
+
+	value := cursor.Value
+	if value == nil {
+		value = cursor.Key
+	}
+
+	if predicate != "" {
+		q = q.Where(predicate, value)
+	}
+	LogCursorPredicate(s.ctx, s.logger, predicate, []interface{}{value}, limit)
+
+	return findCursor(q, items, limit, hasnext)
+}
+
+// PaginateSeek paginates items from the cursor value included, or the items
+// preceding it when before is true, in the cursor order. A nil cursor is
+// unbounded: the first items, or the last ones when before is true.
+func (s *GORMStore) PaginateSeek(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, before bool, hasmore *bool) error {
+	if _, err := ItemsSlice(items); err != nil {
+		return err
+	}
+
+	if _, ok := cursor.(NullCursor); ok {
+		return ErrSeekNotSupported
+	}
+
+	q, err := s.orderByCursor(items, fieldName, reverse, "", "")
+	if err != nil {
+		return err
+	}
+
+	if !before {
+		switch {
+		case cursor == nil:
+		case reverse:
+			q = q.Where(fmt.Sprintf("%s <= ?", fieldName), cursor)
+		default:
+			q = q.Where(fmt.Sprintf("%s >= ?", fieldName), cursor)
+		}
+
+		return findCursor(q, items, limit, hasmore)
+	}
+
+	// walk backwards from the cursor value, then restore the cursor order
+	switch {
+	case cursor == nil:
+	case reverse:
 		q = q.Where(fmt.Sprintf("%s > ?", fieldName), cursor)
+	default:
+		q = q.Where(fmt.Sprintf("%s < ?", fieldName), cursor)
+	}
+	q = q.Order(fmt.Sprintf("%s %s", fieldName, cursorDirection(!reverse)), true)
+
+	if err := findCursor(q, items, limit, hasmore); err != nil {
+		return err
+	}
+
+	return reverseElements(items)
+}
+
+// findCursor fetches one more item than limit to know if there is more.
+func findCursor(q *gorm.DB, items interface{}, limit int64, hasmore *bool) error {
+	err := q.Limit(limit + 1).Find(items).Error
+	if err != nil {
+		return err
 	}
 
-	err := q.Find(s.items).Error
+	len, err := getLen(items)
 	if err != nil {
 		return err
 	}
 
-	len := getLen(s.items)
 	if int64(len) <= limit {
-		*hasnext = false
+		*hasmore = false
 		return nil
 	}
 
-	*hasnext = true
-	_, s.items = popLastElement(s.items)
-	return nil
+	*hasmore = true
+	_, _, err = popLastElement(items)
+	return err
+}
+
+// LogCursorPredicate logs the predicate of a cursor page and its args at
+// debug level, when logger is set. Stores log them with their own logger,
+// apart from the Options.Logger of the paginator.
+func LogCursorPredicate(ctx context.Context, logger *slog.Logger, predicate interface{}, args []interface{}, limit int64) {
+	if logger == nil {
+		return
+	}
+
+	if predicate == nil || predicate == "" {
+		logger.DebugContext(ctx, "paging: cursor predicate, from the first item", "limit", limit)
+		return
+	}
+
+	if len(args) == 0 {
+		logger.DebugContext(ctx, "paging: cursor predicate", "predicate", predicate, "limit", limit)
+		return
+	}
+
+	logger.DebugContext(ctx, "paging: cursor predicate", "predicate", predicate, "args", args, "limit", limit)
+}
+
+// orderByCursor returns the store query ordered by the cursor field, with
+// NULL values first or last and ordered by keyName when nulls is set.
+func (s *GORMStore) orderByCursor(items interface{}, fieldName string, reverse bool, nulls string, keyName string) (*gorm.DB, error) {
+	direction := cursorDirection(reverse)
+
+	orders := getOrders(s.db, items)
+	if len(orders) == 0 {
+		if nulls == "" {
+			return s.db.Order(fmt.Sprintf("%s %s", fieldName, direction)), nil
+		}
+
+		return s.db.
+			Order(fmt.Sprintf("%s %s NULLS %s", fieldName, direction, strings.ToUpper(nulls))).
+			Order(fmt.Sprintf("%s %s", keyName, direction)), nil
+	}
+
+	column, dir, nullsOrder := parseOrder(orders[0])
+	if !sameColumn(column, fieldName) || dir != direction || nullsOrder != strings.ToUpper(nulls) {
+		return nil, ErrIncompatibleOrder
+	}
+
+	return s.db, nil
+}
+
+// cursorDirection returns the SQL order direction.
+func cursorDirection(reverse bool) string {
+	if reverse {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// getOrders returns the ORDER BY terms already set on the query.
+func getOrders(db *gorm.DB, value interface{}) []string {
+	sql := db.Limit(-1).Offset(-1).NewScope(value).CombinedConditionSql()
+
+	idx := strings.LastIndex(strings.ToUpper(sql), " ORDER BY ")
+	if idx < 0 {
+		return nil
+	}
+
+	return splitOrders(sql[idx+len(" ORDER BY "):])
+}
+
+// splitOrders splits an ORDER BY clause on top-level commas.
+func splitOrders(clause string) []string {
+	var (
+		orders []string
+		depth  int
+		start  int
+	)
+
+	for i, r := range clause {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				orders = append(orders, strings.TrimSpace(clause[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	if last := strings.TrimSpace(clause[start:]); last != "" {
+		orders = append(orders, last)
+	}
+
+	return orders
+}
+
+// parseOrder returns the column, the direction (ASC or DESC) and the NULLS
+// ordering (FIRST, LAST or empty) of an ORDER BY term.
+func parseOrder(order string) (column string, direction string, nulls string) {
+	fields := strings.Fields(strings.ToUpper(order))
+	if len(fields) == 0 {
+		return "", "ASC", ""
+	}
+
+	direction = "ASC"
+	for i, field := range fields[1:] {
+		switch {
+		case field == "DESC":
+			direction = "DESC"
+		case field == "NULLS" && i+2 < len(fields):
+			nulls = fields[i+2]
+		}
+	}
+
+	return strings.Fields(order)[0], direction, nulls
+}
+
+// sameColumn returns true if both column names target the same column,
+// ignoring quotes, case and a table prefix missing on one side.
+func sameColumn(a, b string) bool {
+	a, b = unquoteColumn(a), unquoteColumn(b)
+	if a == b {
+		return true
+	}
+
+	if !strings.Contains(b, ".") {
+		a = a[strings.LastIndex(a, ".")+1:]
+	}
+	if !strings.Contains(a, ".") {
+		b = b[strings.LastIndex(b, ".")+1:]
+	}
+
+	return a == b
+}
+
+func unquoteColumn(column string) string {
+	return strings.ToLower(strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(column))
+}
+
+// -----------------------------------------------------------------------------
+// SQL Store
+// -----------------------------------------------------------------------------
+
+// SQLStore is the store for hand-written SQL queries, with joins, GROUP BY,
+// DISTINCT or CTEs. The query is wrapped in a subquery for pages and counts
+// so that counts match the rows returned.
+type SQLStore struct {
+	db           *gorm.DB
+	ctx          context.Context
+	query        SQLQuery
+	logger       *slog.Logger
+	observeCount ObserveFunc
+}
+
+// NewSQLStore returns a new SQL store instance, paginating the rows of
+// query. The query and its args use GORM bind variables (?).
+func NewSQLStore(db *gorm.DB, query string, args []interface{}) (*SQLStore, error) {
+	return &SQLStore{
+		db:    db,
+		ctx:   context.Background(),
+		query: SQLQuery{Query: query, Args: args},
+	}, nil
+}
+
+// WithContext returns a new store logging with ctx, GORM v1 queries don't
+// take a context.
+func (s *SQLStore) WithContext(ctx context.Context) Store {
+	store := *s
+	store.ctx = ctx
+	return &store
+}
+
+// Filter returns a new store restricted to rows matching all filters.
+func (s *SQLStore) Filter(filters Filters) (Store, error) {
+	store := *s
+	store.query = s.query.Filter(filters)
+	return &store, nil
+}
+
+// Sort returns a new store ordered by fieldName instead of its current order.
+func (s *SQLStore) Sort(fieldName string, reverse bool) (Store, error) {
+	store := *s
+	store.query = s.query.Sort(fieldName, reverse)
+	return &store, nil
+}
+
+// DeferredJoin returns a new store paginating sorted offsets with a deferred
+// join on the unique keyName column, see SQLQuery.DeferredKey.
+func (s *SQLStore) DeferredJoin(keyName string) *SQLStore {
+	store := *s
+	store.query.DeferredKey = keyName
+	return &store
+}
+
+// WithLogger returns a new store logging the predicates of cursor pages at
+// debug level.
+func (s *SQLStore) WithLogger(logger *slog.Logger) *SQLStore {
+	store := *s
+	store.logger = logger
+	return &store
+}
+
+// PaginateOffset paginates items with LIMIT and OFFSET on the wrapped query,
+// and counts its rows.
+func (s *SQLStore) PaginateOffset(items interface{}, limit, offset int64, count *int64) error {
+	if err := s.PaginateOffsetItems(items, limit, offset); err != nil {
+		return err
+	}
+
+	return s.observeCount.Run(func() error {
+		return s.Count(items, count)
+	})
+}
+
+// ObserveCount returns a new store running the counts of PaginateOffset
+// with observe.
+func (s *SQLStore) ObserveCount(observe ObserveFunc) Store {
+	store := *s
+	store.observeCount = observe
+	return &store
+}
+
+// PaginateOffsetItems paginates items with LIMIT and OFFSET on the wrapped
+// query, without counting them.
+func (s *SQLStore) PaginateOffsetItems(items interface{}, limit, offset int64) error {
+	if _, err := ItemsSlice(items); err != nil {
+		return err
+	}
+
+	query, args := s.query.OffsetQuery(limit, offset)
+
+	return s.db.Raw(query, args...).Scan(items).Error
+}
+
+// Count counts the rows of the wrapped query.
+func (s *SQLStore) Count(items interface{}, count *int64) error {
+	query, args := s.query.CountQuery()
+
+	return s.db.Raw(query, args...).Row().Scan(count)
+}
+
+// PaginateCursor paginates items with fieldName > cursor (< cursor when
+// reverse is true) on the wrapped query, a nil or empty string cursor starts
+// from the first row. A store sorted on another order returns
+// ErrIncompatibleOrder.
+func (s *SQLStore) PaginateCursor(items interface{}, limit int64, cursor interface{}, fieldName string, reverse bool, hasnext *bool) error {
+	if _, err := ItemsSlice(items); err != nil {
+		return err
+	}
+
+	query, args, err := s.query.CursorQuery(limit+1, cursor, fieldName, reverse)
+	if err != nil {
+		return err
+	}
+
+	predicate, predicateArgs := s.query.CursorPredicate(cursor, fieldName, reverse)
+	LogCursorPredicate(s.ctx, s.logger, predicate, predicateArgs, limit)
+
+	if err := s.db.Raw(query, args...).Scan(items).Error; err != nil {
+		return err
+	}
+
+	len, err := getLen(items)
+	if err != nil {
+		return err
+	}
+
+	*hasnext = int64(len) > limit
+	if *hasnext {
+		_, _, err = popLastElement(items)
+	}
+
+	return err
 }
@@ -0,0 +1,23 @@
+package paging_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulule/paging"
+	"github.com/ulule/paging/internal/pagingtest"
+)
+
+func TestStores_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	db := paging.RebuildDB()
+
+	gormStore, err := paging.NewGORMStore(db.Model(&paging.User{}).Order("id"))
+	is.NoError(err)
+	pagingtest.StoreErrors(t, gormStore, "id")
+
+	sqlStore, err := paging.NewSQLStore(db, "SELECT * FROM users", nil)
+	is.NoError(err)
+	pagingtest.StoreErrors(t, sqlStore, "id")
+}
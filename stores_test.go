@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -52,6 +53,17 @@ func rebuildDB() {
 	}
 }
 
+// pageItems returns the items of a page returned by Next or Previous.
+func pageItems(p Paginator) interface{} {
+	switch p := p.(type) {
+	case *CursorPaginator:
+		return p.Items
+	case *OffsetPaginator:
+		return p.Items
+	}
+	return nil
+}
+
 type User struct {
 	ID           int
 	Number       int
@@ -71,7 +83,7 @@ func TestGORMStore_OffsetPaginator(t *testing.T) {
 	q := db.Model(&User{})
 	q = q.Order("number desc")
 
-	store, err := NewGORMStore(q, &users)
+	store, err := NewGORMStore(q)
 	is.Nil(err)
 
 	options := NewOptions()
@@ -79,7 +91,7 @@ func TestGORMStore_OffsetPaginator(t *testing.T) {
 	paginator, err := NewOffsetPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -101,7 +113,7 @@ func TestGORMStore_OffsetPaginator(t *testing.T) {
 	paginator, err = NewOffsetPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -119,7 +131,7 @@ func TestGORMStore_OffsetPaginator(t *testing.T) {
 	paginator, err = NewOffsetPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -143,9 +155,9 @@ func TestGORMStore_CursorPaginator(t *testing.T) {
 	users := []User{}
 
 	q := db.Model(&User{})
-	q = q.Order("number asc")
+	q = q.Order("id asc")
 
-	store, err := NewGORMStore(q, &users)
+	store, err := NewGORMStore(q)
 	is.Nil(err)
 
 	options := NewOptions()
@@ -153,7 +165,7 @@ func TestGORMStore_CursorPaginator(t *testing.T) {
 	paginator, err := NewCursorPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -171,7 +183,7 @@ func TestGORMStore_CursorPaginator(t *testing.T) {
 	paginator, err = NewCursorPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -184,14 +196,18 @@ func TestGORMStore_CursorPaginator(t *testing.T) {
 	// Next with method
 	//
 
-	np, err := paginator.Next()
+	nextUsers := []User{}
+	np, err := paginator.Next(&nextUsers)
 	is.Nil(err)
 	nextPaginator := np.(*CursorPaginator)
 
 	is.Nil(err)
 
+	// the next page has its own items
+	is.Equal(21, users[0].Number)
+
 	is.Equal(int64(20), nextPaginator.Limit)
-	is.Equal(len(users), 20)
+	is.Equal(len(nextUsers), 20)
 	is.Equal(int(40), nextPaginator.Cursor)
 	is.False(nextPaginator.PreviousURI.Valid) // null
 	is.Equal("?limit=20&since=60", nextPaginator.NextURI.String)
@@ -200,12 +216,12 @@ func TestGORMStore_CursorPaginator(t *testing.T) {
 	// test previous
 	//
 
-	pp, err := nextPaginator.Previous()
+	pp, err := nextPaginator.Previous(&[]User{})
 	is.Nil(pp)
 	is.NotNil(err)
 
 	// Check order asc
-	is.Equal(41, users[0].Number)
+	is.Equal(41, nextUsers[0].Number)
 }
 
 func TestGORMStore_CursorPaginator_Date(t *testing.T) {
@@ -220,7 +236,7 @@ func TestGORMStore_CursorPaginator_Date(t *testing.T) {
 	q := db.Model(&User{})
 	q = q.Order("date_creation desc")
 
-	store, err := NewGORMStore(q, &users)
+	store, err := NewGORMStore(q)
 	is.Nil(err)
 
 	options := NewOptions()
@@ -232,7 +248,7 @@ func TestGORMStore_CursorPaginator_Date(t *testing.T) {
 
 	paginator, err := NewCursorPaginator(store, request, options)
 	is.Nil(err)
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -250,7 +266,7 @@ func TestGORMStore_CursorPaginator_Date(t *testing.T) {
 	paginator, err = NewCursorPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 
 	is.Equal(int64(20), paginator.Limit)
@@ -263,30 +279,36 @@ func TestGORMStore_CursorPaginator_Date(t *testing.T) {
 	// // Next again
 	// //
 
-	np, err := paginator.Next()
+	nextUsers := []User{}
+	np, err := paginator.Next(&nextUsers)
 	is.Nil(err)
 	nextPaginator := np.(*CursorPaginator)
 
 	is.Equal(int64(20), nextPaginator.Limit)
-	is.Equal(len(users), 20)
+	is.Equal(len(nextUsers), 20)
 	is.False(nextPaginator.PreviousURI.Valid) // null
 	is.Equal("?limit=20&since-date=1484649256", nextPaginator.NextURI.String)
-	is.Equal(60, users[0].Number)
+	is.Equal(60, nextUsers[0].Number)
+
+	// the current page is left untouched
+	np, err = paginator.Next(&[]User{})
+	is.Nil(err)
+	is.Equal(60, (*pageItems(np).(*[]User))[0].Number)
 
 	// //
 	// // End of cursor
 	// //
 
 	// end with next
-	np, err = paginator.Next()
+	np, err = np.Next(&[]User{})
 	is.Nil(err)
-	is.Equal(40, users[0].Number)
+	is.Equal(40, (*pageItems(np).(*[]User))[0].Number)
 
-	np, err = np.Next()
+	np, err = np.Next(&[]User{})
 	is.Nil(err)
-	is.Equal(20, users[0].Number)
+	is.Equal(20, (*pageItems(np).(*[]User))[0].Number)
 
-	np, err = np.Next()
+	np, err = np.Next(&[]User{})
 	is.Error(err)
 
 	// end with request
@@ -295,7 +317,7 @@ func TestGORMStore_CursorPaginator_Date(t *testing.T) {
 	paginator, err = NewCursorPaginator(store, request, options)
 	is.Nil(err)
 
-	err = paginator.Page()
+	err = paginator.Page(&users)
 	is.Nil(err)
 	is.False(paginator.NextURI.Valid) // null
 	is.Empty(users)
@@ -304,7 +326,7 @@ func TestGORMStore_CursorPaginator_Date(t *testing.T) {
 	// // test previous
 	// //
 
-	pp, err := nextPaginator.Previous()
+	pp, err := nextPaginator.Previous(&[]User{})
 	is.Nil(pp)
 	is.NotNil(err)
 
@@ -315,14 +337,461 @@ func TestGORMStore_PaginateCursor_HasNext(t *testing.T) {
 	rebuildDB()
 
 	var items []User
-	s := GORMStore{db: db.Model(&User{}), items: &items}
+	s := GORMStore{db: db.Model(&User{})}
 
 	var hasnext bool
-	is.NoError(s.PaginateCursor(99, 0, DefaultCursorDBName, false, &hasnext))
+	is.NoError(s.PaginateCursor(&items, 99, 0, DefaultCursorDBName, false, &hasnext))
 	is.Equal(99, len(items))
 	is.True(hasnext)
 
-	is.NoError(s.PaginateCursor(100, 0, DefaultCursorDBName, false, &hasnext))
+	is.NoError(s.PaginateCursor(&items, 100, 0, DefaultCursorDBName, false, &hasnext))
 	is.Equal(100, len(items))
 	is.False(hasnext)
 }
+
+func TestGORMStore_PaginateCursor_Order(t *testing.T) {
+	is := assert.New(t)
+	rebuildDB()
+
+	var hasnext bool
+
+	// no order, the cursor order is added
+	var items []User
+	s := GORMStore{db: db.Model(&User{})}
+	is.NoError(s.PaginateCursor(&items, 10, 0, DefaultCursorDBName, false, &hasnext))
+	is.Equal(1, items[0].ID)
+	is.Equal(10, items[9].ID)
+
+	items = nil
+	s = GORMStore{db: db.Model(&User{})}
+	is.NoError(s.PaginateCursor(&items, 10, 1000, DefaultCursorDBName, true, &hasnext))
+	is.Equal(100, items[0].ID)
+	is.Equal(91, items[9].ID)
+
+	// matching order
+	items = nil
+	s = GORMStore{db: db.Model(&User{}).Order(`"users"."id" DESC`).Order("name")}
+	is.NoError(s.PaginateCursor(&items, 10, 1000, DefaultCursorDBName, true, &hasnext))
+	is.Equal(100, items[0].ID)
+
+	// incompatible orders
+	s = GORMStore{db: db.Model(&User{}).Order("id desc")}
+	is.Equal(ErrIncompatibleOrder, s.PaginateCursor(&items, 10, 0, DefaultCursorDBName, false, &hasnext))
+
+	s = GORMStore{db: db.Model(&User{}).Order("name").Order("id")}
+	is.Equal(ErrIncompatibleOrder, s.PaginateCursor(&items, 10, 0, DefaultCursorDBName, false, &hasnext))
+}
+
+type Article struct {
+	ID    string `gorm:"primary_key"`
+	Title string
+}
+
+func TestGORMStore_CursorPaginator_UUID(t *testing.T) {
+	is := assert.New(t)
+
+	is.NoError(db.DropTableIfExists(&Article{}).Error)
+	is.NoError(db.CreateTable(&Article{}).Error)
+	for i := 1; i <= 5; i++ {
+		is.NoError(db.Create(&Article{
+			ID:    fmt.Sprintf("%08x-0000-4000-8000-000000000000", i*0x1000000),
+			Title: fmt.Sprintf("article-%d", i),
+		}).Error)
+	}
+
+	options := NewOptions()
+	options.CursorOptions.Mode = UUIDModeCursor
+
+	articles := []Article{}
+	store, err := NewGORMStore(db.Model(&Article{}))
+	is.NoError(err)
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=2", nil)
+	paginator, err := NewCursorPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&articles))
+	is.Len(articles, 2)
+	is.Equal("article-1", articles[0].Title)
+	is.Equal("?limit=2&since=02000000-0000-4000-8000-000000000000", paginator.NextURI.String)
+
+	request, _ = http.NewRequest("GET", paginator.NextURI.String, nil)
+	paginator, err = NewCursorPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&articles))
+	is.Equal("article-3", articles[0].Title)
+
+	nextArticles := []Article{}
+	np, err := paginator.Next(&nextArticles)
+	is.NoError(err)
+	is.False(np.HasNext())
+	is.Len(nextArticles, 1)
+	is.Equal("article-5", nextArticles[0].Title)
+
+	// reverse with no cursor starts from the last item
+	options.CursorOptions.Reverse = true
+	request, _ = http.NewRequest("GET", "http://example.com?limit=2", nil)
+	paginator, err = NewCursorPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&articles))
+	is.Equal("article-5", articles[0].Title)
+	is.Equal("article-4", articles[1].Title)
+}
+
+type Event struct {
+	ID       int
+	Rank     sql.NullInt64
+	StartsAt *time.Time
+}
+
+func rebuildEvents() {
+	db.DropTableIfExists(&Event{})
+	db.CreateTable(&Event{})
+	for i := 1; i <= 10; i++ {
+		event := Event{ID: i}
+		// odd events have no rank and no start date
+		if i%2 == 0 {
+			startsAt := time.Unix(refDate, 0).Add(time.Duration(i) * time.Hour)
+			event.Rank = sql.NullInt64{Int64: int64(100 - i), Valid: true}
+			event.StartsAt = &startsAt
+		}
+		db.Create(&event)
+	}
+}
+
+func eventIDs(events []Event) []int {
+	ids := make([]int, len(events))
+	for i := range events {
+		ids[i] = events[i].ID
+	}
+	return ids
+}
+
+func TestGORMStore_CursorPaginator_NullsLast(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildEvents()
+
+	options := NewOptions()
+	options.CursorOptions.DBName = "rank"
+	options.CursorOptions.StructName = "Rank"
+	options.CursorOptions.Nulls = NullsLast
+
+	events := []Event{}
+	store, err := NewGORMStore(db.Model(&Event{}))
+	is.NoError(err)
+
+	var ids []int
+	uri := "http://example.com?limit=3"
+	for {
+		request, _ := http.NewRequest("GET", uri, nil)
+		paginator, err := NewCursorPaginator(store, request, options)
+		is.NoError(err)
+		is.NoError(paginator.Page(&events))
+		ids = append(ids, eventIDs(events)...)
+
+		if !paginator.NextURI.Valid {
+			break
+		}
+		uri = paginator.NextURI.String
+	}
+
+	// ranked events by rank (100 - id), then unranked events by id
+	is.Equal([]int{10, 8, 6, 4, 2, 1, 3, 5, 7, 9}, ids)
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=3&since=null:3", nil)
+	is.Equal(CursorType, GetPaginationType(request, options))
+}
+
+func TestGORMStore_CursorPaginator_NullsFirst_DateMode_Reverse(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildEvents()
+
+	options := NewOptions()
+	options.CursorOptions.Mode = DateModeCursor
+	options.CursorOptions.DBName = "starts_at"
+	options.CursorOptions.StructName = "StartsAt"
+	options.CursorOptions.Reverse = true
+	options.CursorOptions.Nulls = NullsFirst
+
+	events := []Event{}
+	store, err := NewGORMStore(db.Model(&Event{}))
+	is.NoError(err)
+
+	request, _ := http.NewRequest("GET", "http://example.com?limit=4", nil)
+	paginator, err := NewCursorPaginator(store, request, options)
+	is.NoError(err)
+	is.NoError(paginator.Page(&events))
+	is.Equal([]int{9, 7, 5, 3}, eventIDs(events))
+	is.Equal("?limit=4&since=null%3A3", paginator.NextURI.String)
+
+	np, err := paginator.Next(&[]Event{})
+	is.NoError(err)
+	is.Equal([]int{1, 10, 8, 6}, eventIDs(*pageItems(np).(*[]Event)))
+
+	np, err = np.Next(&[]Event{})
+	is.NoError(err)
+	is.Equal([]int{4, 2}, eventIDs(*pageItems(np).(*[]Event)))
+	is.False(np.HasNext())
+
+	// an existing order must match the nulls ordering
+	store, err = NewGORMStore(db.Model(&Event{}).Order("starts_at desc"))
+	is.NoError(err)
+	paginator, err = NewCursorPaginator(store, request, options)
+	is.NoError(err)
+	is.Equal(ErrIncompatibleOrder, paginator.Page(&events))
+}
+
+func TestSQLStore_OffsetPaginator_GroupBy(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	type bucket struct {
+		Bucket int
+		Total  int
+	}
+
+	buckets := []bucket{}
+	store, err := NewSQLStore(db, "SELECT number / 10 AS bucket, COUNT(*) AS total FROM users WHERE number > ? GROUP BY number / 10", []interface{}{5})
+	is.NoError(err)
+
+	sorted, err := store.Sort("bucket", true)
+	is.NoError(err)
+
+	paginator, err := NewOffsetPaginatorFromPageRequest(sorted, PageRequest{Limit: 3, Offset: 1}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&buckets))
+
+	// 11 buckets, not the 95 grouped rows
+	is.Equal(int64(11), paginator.Count)
+	is.Equal([]bucket{{9, 10}, {8, 10}, {7, 10}}, buckets)
+	is.Equal("?limit=3&offset=4", paginator.NextURI.String)
+}
+
+func TestSQLStore_CursorPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewSQLStore(db, "WITH recent AS (SELECT * FROM users WHERE number > 50) SELECT DISTINCT * FROM recent", nil)
+	is.NoError(err)
+
+	filtered, err := store.Filter(Filters{{DBName: "number", Operator: FilterLessThanOrEqual, Value: 80}})
+	is.NoError(err)
+
+	paginator, err := NewCursorPaginatorFromPageRequest(filtered, PageRequest{Limit: 20, Cursor: int64(55)}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Len(users, 20)
+	is.Equal(56, users[0].ID)
+	is.True(paginator.HasNext())
+
+	nextUsers := []User{}
+	np, err := paginator.Next(&nextUsers)
+	is.NoError(err)
+	is.Len(nextUsers, 5)
+	is.Equal(76, nextUsers[0].ID)
+	is.False(np.HasNext())
+
+	var hasnext bool
+	is.Equal(ErrNullCursorNotSupported, store.PaginateCursor(&users, 10, NullCursor{}, "id", false, &hasnext))
+
+	sorted, err := store.Sort("name", false)
+	is.NoError(err)
+	is.Equal(ErrIncompatibleOrder, sorted.PaginateCursor(&users, 10, nil, "id", false, &hasnext))
+}
+
+func TestGORMStore_Concurrent(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewGORMStore(db.Model(&User{}).Order("id"))
+	is.NoError(err)
+
+	// paginators share the store and the options
+	options := NewOptions()
+
+	var wg sync.WaitGroup
+	for i := int64(0); i < 10; i++ {
+		wg.Add(2)
+
+		go func(offset int64) {
+			defer wg.Done()
+
+			users := []User{}
+			p, err := NewOffsetPaginatorFromPageRequest(store, PageRequest{Limit: 5, Offset: offset}, options)
+			is.NoError(err)
+			is.NoError(p.Page(&users))
+			is.Equal(int64(100), p.Count)
+			if is.Len(users, 5) {
+				is.Equal(int(offset)+1, users[0].ID)
+			}
+		}(i * 10)
+
+		go func(cursor int64) {
+			defer wg.Done()
+
+			users := []User{}
+			p, err := NewCursorPaginatorFromPageRequest(store, PageRequest{Limit: 5, Cursor: cursor}, options)
+			is.NoError(err)
+			is.NoError(p.Page(&users))
+
+			nextUsers := []User{}
+			_, err = p.Next(&nextUsers)
+			is.NoError(err)
+			if is.Len(users, 5) && is.Len(nextUsers, 5) {
+				is.Equal(int(cursor)+1, users[0].ID)
+				is.Equal(int(cursor)+6, nextUsers[0].ID)
+			}
+		}(i * 10)
+	}
+	wg.Wait()
+}
+
+type Membership struct {
+	ID     int
+	UserID int
+	Group  string
+}
+
+func TestGORMStore_PaginateOffset_Count(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+	db.DropTableIfExists(&Membership{})
+	db.CreateTable(&Membership{})
+	for i := 1; i <= 10; i++ {
+		db.Create(&Membership{UserID: i, Group: "a"})
+		db.Create(&Membership{UserID: i, Group: "b"})
+	}
+
+	type bucket struct {
+		Bucket int
+		Total  int
+	}
+
+	tests := []struct {
+		name  string
+		q     *gorm.DB
+		items interface{}
+		count int64
+	}{
+		{"group", db.Table("users").Select("number / 10 AS bucket, COUNT(*) AS total").Group("number / 10").Order("bucket desc"), &[]bucket{}, 11},
+		{"having", db.Table("users").Select("number / 10 AS bucket, COUNT(*) AS total").Group("number / 10").Having("COUNT(*) > ?", 9), &[]bucket{}, 9},
+		{"distinct", db.Table("users").Select("DISTINCT number / 10 AS bucket"), &[]bucket{}, 11},
+		{"join", db.Model(&User{}).Joins("JOIN memberships ON memberships.user_id = users.id").Order("users.id"), &[]User{}, 20},
+		{"distinct join", db.Model(&User{}).Select("DISTINCT users.*").Joins("JOIN memberships ON memberships.user_id = users.id"), &[]User{}, 10},
+	}
+
+	for _, tt := range tests {
+		store, err := NewGORMStore(tt.q)
+		is.NoError(err)
+
+		var count int64
+		is.NoError(store.PaginateOffset(tt.items, 5, 0, &count), tt.name)
+		is.Equal(tt.count, count, tt.name)
+		len, err := getLen(tt.items)
+		is.NoError(err)
+		is.Equal(5, len, tt.name)
+	}
+}
+
+func TestGORMStore_DeferredJoin(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	filters := Filters{{DBName: "number", Operator: FilterGreaterThan, Value: 10}}
+
+	for _, q := range []*gorm.DB{
+		db.Model(&User{}).Order("id"),
+		db.Model(&User{}).Order("number desc"),
+		db.Model(&User{}).Where("number % 3 = ?", 0).Order("name, id desc"),
+	} {
+		plain, err := NewGORMStore(q)
+		is.NoError(err)
+		deferred := plain.DeferredJoin("id")
+
+		filteredPlain, err := plain.Filter(filters)
+		is.NoError(err)
+		filteredDeferred, err := deferred.Filter(filters)
+		is.NoError(err)
+
+		// the deferred join keeps the items and their order
+		for _, offset := range []int64{0, 7, 25, 95} {
+			want, got := []User{}, []User{}
+			is.NoError(plain.PaginateOffsetItems(&want, 10, offset))
+			is.NoError(deferred.PaginateOffsetItems(&got, 10, offset))
+			is.Equal(want, got)
+
+			is.NoError(filteredPlain.(*GORMStore).PaginateOffsetItems(&want, 10, offset))
+			is.NoError(filteredDeferred.(*GORMStore).PaginateOffsetItems(&got, 10, offset))
+			is.Equal(want, got)
+		}
+	}
+
+	// the key breaks the ties of the order, pages don't overlap
+	tied, err := NewGORMStore(db.Model(&User{}).Order("number % 3"))
+	is.NoError(err)
+	ids := map[int]bool{}
+	for offset := int64(0); offset < 100; offset += 10 {
+		users := []User{}
+		is.NoError(tied.DeferredJoin("id").PaginateOffsetItems(&users, 10, offset))
+		for _, user := range users {
+			ids[user.ID] = true
+		}
+	}
+	is.Len(ids, 100)
+
+	// unordered stores have no order to keep
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+	want, got := []User{}, []User{}
+	is.NoError(store.PaginateOffsetItems(&want, 10, 20))
+	is.NoError(store.DeferredJoin("id").PaginateOffsetItems(&got, 10, 20))
+	is.Equal(want, got)
+
+	sorted, err := store.DeferredJoin("users.id").Sort("number", true)
+	is.NoError(err)
+
+	users := []User{}
+	paginator, err := NewOffsetPaginatorFromPageRequest(sorted, PageRequest{Limit: 10, Offset: 90}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(100), paginator.Count)
+	is.Len(users, 10)
+	is.Equal(10, users[0].Number)
+	is.Equal(1, users[9].Number)
+}
+
+func TestSQLStore_DeferredJoin(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	store, err := NewSQLStore(db, "SELECT * FROM users WHERE number > ?", []interface{}{5})
+	is.NoError(err)
+
+	filtered, err := store.DeferredJoin("id").Filter(Filters{{DBName: "number", Operator: FilterLessThanOrEqual, Value: 90}})
+	is.NoError(err)
+	sorted, err := filtered.(*SQLStore).Sort("number", true)
+	is.NoError(err)
+
+	query, args := sorted.(*SQLStore).query.OffsetQuery(10, 80)
+	is.Equal("SELECT * FROM (SELECT * FROM users WHERE number > ?) AS paging WHERE number <= ? AND "+
+		"id IN (SELECT * FROM (SELECT id FROM (SELECT * FROM users WHERE number > ?) AS paging WHERE number <= ? ORDER BY number DESC, id LIMIT ? OFFSET ?) AS paging_keys) "+
+		"ORDER BY number DESC, id", query)
+	is.Equal([]interface{}{5, 90, 5, 90, int64(10), int64(80)}, args)
+
+	users := []User{}
+	paginator, err := NewOffsetPaginatorFromPageRequest(sorted, PageRequest{Limit: 10, Offset: 80}, nil)
+	is.NoError(err)
+	is.NoError(paginator.Page(&users))
+	is.Equal(int64(85), paginator.Count)
+	is.Len(users, 5)
+	is.Equal(10, users[0].Number)
+	is.Equal(6, users[4].Number)
+}
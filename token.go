@@ -0,0 +1,71 @@
+package paging
+
+import (
+	"encoding/base64"
+)
+
+// -----------------------------------------------------------------------------
+// Paginator with page token
+// -----------------------------------------------------------------------------
+
+// TokenPaginator is a CursorPaginator driven by a page size and an opaque
+// page token, as described by AIP-158 (page_size, page_token and
+// next_page_token).
+type TokenPaginator struct {
+	*CursorPaginator
+}
+
+// NewTokenPaginator returns a new TokenPaginator instance.
+//
+// A zero page size uses the default limit, a page size above the maximum
+// limit is coerced to it and an empty page token starts from the first item.
+// It returns ErrInvalidLimitOrOffset for a negative page size and
+// ErrInvalidCursor for an invalid page token.
+func NewTokenPaginator(store Store, pageSize int64, pageToken string, options *Options) (*TokenPaginator, error) {
+	if options == nil {
+		options = NewOptions()
+	}
+
+	cursor, err := newCursorPaginator(store, options)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &TokenPaginator{cursor}
+
+	if pageSize != 0 {
+		if err := p.setLimit(pageSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if pageToken != "" {
+		if err := p.setOpaqueCursor(pageToken); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// NextPageToken returns the next page token, empty on the last page.
+func (p *TokenPaginator) NextPageToken() (string, error) {
+	if !p.HasNext() {
+		return "", nil
+	}
+
+	if _, ok := p.Store.(NextCursorer); ok {
+		return base64.RawURLEncoding.EncodeToString([]byte(formatCursor(p.next))), nil
+	}
+
+	items, err := sliceValue(p.Items)
+	if err != nil {
+		return "", err
+	}
+
+	if items.Len() == 0 {
+		return "", nil
+	}
+
+	return encodeOpaqueCursor(p.Options.CursorOptions, items.Index(items.Len()-1))
+}
@@ -0,0 +1,59 @@
+package paging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenPaginator(t *testing.T) {
+	is := assert.New(t)
+
+	rebuildDB()
+
+	users := []User{}
+	store, err := NewGORMStore(db.Model(&User{}))
+	is.NoError(err)
+
+	options := NewOptions()
+	options.MaxLimit = 30
+
+	var (
+		ids   []int
+		token string
+		pages int
+	)
+
+	for {
+		p, err := NewTokenPaginator(store, 1000, token, options)
+		is.NoError(err)
+		is.Equal(int64(30), p.Limit)
+		is.NoError(p.Page(&users))
+
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+		pages++
+
+		token, err = p.NextPageToken()
+		is.NoError(err)
+		if token == "" {
+			break
+		}
+	}
+
+	is.Equal(4, pages)
+	is.Len(ids, 100)
+	is.Equal(1, ids[0])
+	is.Equal(100, ids[99])
+
+	p, err := NewTokenPaginator(store, 0, "", options)
+	is.NoError(err)
+	is.Equal(int64(DefaultLimit), p.Limit)
+
+	_, err = NewTokenPaginator(store, -1, "", options)
+	is.Equal(ErrInvalidLimitOrOffset, err)
+
+	_, err = NewTokenPaginator(store, 10, "not a token", options)
+	is.Equal(ErrInvalidCursor, err)
+}
@@ -1,12 +1,23 @@
 package paging
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// ErrInvalidItems is returned by paginators when the store items aren't a
+// slice of structs or maps, or lack the cursor field.
+var ErrInvalidItems = errors.New("invalid items")
+
 // ValidateLimitOffset returns true if limit and offset values are valid
 func ValidateLimitOffset(limit int64, offset int64) bool {
 	values := []int64{limit, offset}
@@ -22,20 +33,32 @@ func ValidateLimitOffset(limit int64, offset int64) bool {
 
 // GetLimitFromRequest returns current limit.
 func GetLimitFromRequest(request *http.Request, options *Options) int64 {
+	return getLimit(request.Context(), request.URL.Query(), options)
+}
+
+// GetLimitFromValues returns current limit from query string values.
+func GetLimitFromValues(values url.Values, options *Options) int64 {
+	return getLimit(context.Background(), values, options)
+}
+
+// getLimit returns current limit from query string values, logging with
+// ctx.
+func getLimit(ctx context.Context, values url.Values, options *Options) int64 {
 	var (
 		limit int64
 		err   error
 	)
 
-	requestLimit := request.URL.Query().Get(options.LimitKeyName)
+	requestLimit := values.Get(options.LimitKeyName)
 
 	if requestLimit != "" {
 		limit, err = strconv.ParseInt(requestLimit, 10, 64)
 		if err != nil {
 			limit = options.DefaultLimit
-
+			logDebug(ctx, options, "paging: invalid limit, using the default limit", "limit", requestLimit, "default_limit", limit)
 		}
 		if options.MaxLimit > 0 && limit > options.MaxLimit {
+			logDebug(ctx, options, "paging: limit clamped to the maximum limit", "limit", limit, "max_limit", options.MaxLimit)
 			limit = options.MaxLimit
 		}
 	} else {
@@ -47,17 +70,29 @@ func GetLimitFromRequest(request *http.Request, options *Options) int64 {
 
 // GetOffsetFromRequest returns current offset.
 func GetOffsetFromRequest(request *http.Request, options *Options) int64 {
+	return getOffset(request.Context(), request.URL.Query(), options)
+}
+
+// GetOffsetFromValues returns current offset from query string values.
+func GetOffsetFromValues(values url.Values, options *Options) int64 {
+	return getOffset(context.Background(), values, options)
+}
+
+// getOffset returns current offset from query string values, logging with
+// ctx.
+func getOffset(ctx context.Context, values url.Values, options *Options) int64 {
 	var (
 		offset int64
 		err    error
 	)
 
-	requestOffset := request.URL.Query().Get(options.OffsetKeyName)
+	requestOffset := values.Get(options.OffsetKeyName)
 
 	if requestOffset != "" {
 		offset, err = strconv.ParseInt(requestOffset, 10, 64)
 		if err != nil {
 			offset = 0
+			logDebug(ctx, options, "paging: invalid offset, using the first offset", "offset", requestOffset)
 		}
 	} else {
 		offset = 0
@@ -87,6 +122,170 @@ func GetCursorFromRequest(request *http.Request, options *Options) int64 {
 	return cursor
 }
 
+// GetCursorValueFromRequest returns current cursor typed according to the
+// cursor mode: an int64 ID, a time.Time or a string. An invalid or missing
+// cursor returns the zero value of the mode.
+//
+// When CursorOptions.Nulls is set, it returns a NullCursor.
+func GetCursorValueFromRequest(request *http.Request, options *Options) interface{} {
+	return getCursorValue(request.Context(), request.URL.Query(), options)
+}
+
+// GetCursorValueFromValues returns current cursor from query string values,
+// see GetCursorValueFromRequest.
+func GetCursorValueFromValues(values url.Values, options *Options) interface{} {
+	return getCursorValue(context.Background(), values, options)
+}
+
+// getCursorValue returns current cursor from query string values, logging
+// with ctx.
+func getCursorValue(ctx context.Context, values url.Values, options *Options) interface{} {
+	mode := options.CursorOptions.Mode
+	raw := values.Get(options.CursorOptions.KeyName)
+
+	if options.CursorOptions.Nulls != "" {
+		return parseNullCursor(options.CursorOptions, raw)
+	}
+
+	cursor, err := parseCursor(mode, raw)
+	if err != nil {
+		cursor, _ = parseCursor(mode, "")
+		logDebug(ctx, options, "paging: invalid cursor, using the first item", "cursor", raw, "mode", mode, "error", err)
+	}
+
+	return cursor
+}
+
+// parseNullCursor parses a query string cursor on a nullable column, which is
+// either a value or the key of the last row prefixed by NullCursorPrefix.
+func parseNullCursor(options *CursorOptions, raw string) NullCursor {
+	cursor := NullCursor{
+		KeyDBName: options.KeyDBName,
+		Nulls:     options.Nulls,
+	}
+
+	if raw == "" {
+		return cursor
+	}
+
+	if strings.HasPrefix(raw, NullCursorPrefix) {
+		key := strings.TrimPrefix(raw, NullCursorPrefix)
+		if id, err := strconv.ParseInt(key, 10, 64); err == nil {
+			cursor.Key = id
+		} else {
+			cursor.Key = key
+		}
+		return cursor
+	}
+
+	if value, err := parseCursor(options.Mode, raw); err == nil {
+		cursor.Value = value
+	}
+
+	return cursor
+}
+
+// parseCursor parses a query string cursor according to the cursor mode.
+func parseCursor(mode string, raw string) (interface{}, error) {
+	switch mode {
+	case StringModeCursor:
+		return raw, nil
+	case UUIDModeCursor:
+		return parseUUID(raw)
+	case ULIDModeCursor:
+		return parseULID(raw)
+	}
+
+	var (
+		cursor int64
+		err    error
+	)
+
+	if raw != "" {
+		cursor, err = strconv.ParseInt(raw, 10, 64)
+	}
+
+	if mode == DateModeCursor {
+		// time in cursor is standard timestamp (second)
+		return time.Unix(cursor, 0), err
+	}
+
+	return cursor, err
+}
+
+// parseUUID validates an UUID and returns its lower case canonical form.
+func parseUUID(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	uuid := strings.ToLower(strings.Trim(raw, "{}"))
+	if len(uuid) != 36 {
+		return "", fmt.Errorf("invalid UUID %q", raw)
+	}
+
+	for i, r := range uuid {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return "", fmt.Errorf("invalid UUID %q", raw)
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdef", r) {
+				return "", fmt.Errorf("invalid UUID %q", raw)
+			}
+		}
+	}
+
+	return uuid, nil
+}
+
+// parseULID validates an ULID and returns its upper case canonical form.
+func parseULID(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	ulid := strings.ToUpper(raw)
+	if len(ulid) != 26 || ulid[0] > '7' {
+		return "", fmt.Errorf("invalid ULID %q", raw)
+	}
+
+	for _, r := range ulid {
+		if !strings.ContainsRune("0123456789ABCDEFGHJKMNPQRSTVWXYZ", r) {
+			return "", fmt.Errorf("invalid ULID %q", raw)
+		}
+	}
+
+	return ulid, nil
+}
+
+// isStringCursorMode returns true if the cursor mode works with strings.
+func isStringCursorMode(mode string) bool {
+	return mode == StringModeCursor || mode == UUIDModeCursor || mode == ULIDModeCursor
+}
+
+// formatCursor returns the string form of a cursor value.
+func formatCursor(cursor interface{}) string {
+	switch c := cursor.(type) {
+	case NullCursor:
+		if c.Value != nil {
+			return formatCursor(c.Value)
+		}
+		return NullCursorPrefix + formatCursor(c.Key)
+	case string:
+		return c
+	case []byte:
+		return string(c)
+	case interface{ Hex() string }:
+		// ObjectIDs
+		return c.Hex()
+	case fmt.Stringer:
+		return c.String()
+	}
+	return fmt.Sprint(cursor)
+}
+
 // GenerateOffsetURI generates the pagination URI.
 func GenerateOffsetURI(limit int64, offset int64, options *Options) string {
 	if options == nil {
@@ -106,11 +305,11 @@ func GenerateCursorURI(limit int64, cursor interface{}, options *Options) string
 		return ""
 	}
 	return fmt.Sprintf(
-		"?%s=%d&%s=%d",
+		"?%s=%d&%s=%s",
 		options.LimitKeyName,
 		limit,
 		options.CursorOptions.KeyName,
-		cursor)
+		url.QueryEscape(formatCursor(cursor)))
 }
 
 // GetPaginationType returns the pagination type "offeset|cursor"
@@ -121,6 +320,20 @@ func GetPaginationType(request *http.Request, options *Options) string {
 		options = NewOptions()
 	}
 
+	if options.CursorOptions.Nulls != "" || isStringCursorMode(options.CursorOptions.Mode) {
+		switch cursor := GetCursorValueFromRequest(request, options).(type) {
+		case NullCursor:
+			if cursor.Value != nil || cursor.Key != nil {
+				return CursorType
+			}
+		case string:
+			if cursor != "" {
+				return CursorType
+			}
+		}
+		return OffsetType
+	}
+
 	if cursor := GetCursorFromRequest(request, options); cursor > 0 {
 		return CursorType
 	}
@@ -128,59 +341,213 @@ func GetPaginationType(request *http.Request, options *Options) string {
 	return OffsetType
 }
 
-func getLastElementField(array interface{}, fieldname string) interface{} {
-	value := reflect.ValueOf(array)
-	kind := value.Kind()
-	if kind == reflect.Ptr {
-		value = value.Elem()
-		kind = value.Kind()
+// parseTime accepts timestamps (second), RFC 3339 dates, plain dates and months.
+func parseTime(raw string) (time.Time, error) {
+	if timestamp, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(timestamp, 0), nil
+	}
+
+	var (
+		t   time.Time
+		err error
+	)
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "2006-01"} {
+		if t, err = time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
 	}
 
-	if kind != reflect.Array && kind != reflect.Slice {
-		panic(fmt.Sprintf("can't get last element of a value of type %T", array))
+	return t, err
+}
+
+// getLastElementField returns the field of the last element, nil when the
+// slice is empty.
+func getLastElementField(array interface{}, fieldname string) (interface{}, error) {
+	value, err := sliceValue(array)
+	if err != nil {
+		return nil, err
 	}
 
 	if value.Len() == 0 {
-		return nil
+		return nil, nil
+	}
+
+	return getElementField(value.Index(value.Len()-1), fieldname)
+}
+
+// getElementField returns the field of a struct or a map element, or of a
+// pointer to them. The field is looked up by name, promoted fields included,
+// then by db or json tag, a dotted name looks up nested fields. A nil
+// pointer on the way is a NULL value.
+func getElementField(element reflect.Value, fieldname string) (interface{}, error) {
+	for _, name := range strings.Split(fieldname, ".") {
+		for element.Kind() == reflect.Ptr || element.Kind() == reflect.Interface {
+			if element.IsNil() {
+				return nil, nil
+			}
+			element = element.Elem()
+		}
+
+		switch {
+		case element.Kind() == reflect.Struct:
+			field, ok := structField(element, name)
+			if !ok {
+				return nil, fmt.Errorf("%w: no field %q in %s", ErrInvalidItems, fieldname, element.Type())
+			}
+			if !field.IsValid() {
+				return nil, nil
+			}
+			element = field
+		case element.Kind() == reflect.Map && element.Type().Key().Kind() == reflect.String:
+			field := element.MapIndex(reflect.ValueOf(name).Convert(element.Type().Key()))
+			if !field.IsValid() {
+				return nil, fmt.Errorf("%w: no key %q in %s", ErrInvalidItems, fieldname, element.Type())
+			}
+			element = field
+		case !element.IsValid():
+			return nil, fmt.Errorf("%w: can't get field %q of an invalid element", ErrInvalidItems, fieldname)
+		default:
+			return nil, fmt.Errorf("%w: can't get field %q of an element of type %s", ErrInvalidItems, fieldname, element.Type())
+		}
 	}
 
-	last := value.Index(value.Len() - 1)
-	if last.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("can't get fieldname %q of an element of type %T", fieldname, last.Interface()))
+	if !element.CanInterface() {
+		return nil, fmt.Errorf("%w: field %q is unexported", ErrInvalidItems, fieldname)
 	}
 
-	return last.FieldByName(fieldname).Interface()
+	return element.Interface(), nil
 }
 
-func getLen(array interface{}) int {
+// structField returns the field of a struct by name, or by db or json tag
+// in the struct and its embedded structs.
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	if sf, ok := v.Type().FieldByName(name); ok {
+		// a nil embedded pointer is a NULL value
+		field, _ := v.FieldByIndexErr(sf.Index)
+		return field, true
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tagName(field.Tag.Get("db")) == name || tagName(field.Tag.Get("json")) == name {
+			return v.Field(i), true
+		}
+
+		if !field.Anonymous {
+			continue
+		}
+
+		embedded := v.Field(i)
+		if embedded.Kind() == reflect.Ptr {
+			if embedded.IsNil() {
+				continue
+			}
+			embedded = embedded.Elem()
+		}
+
+		if embedded.Kind() == reflect.Struct {
+			if f, ok := structField(embedded, name); ok {
+				return f, true
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// tagName returns the name of a struct tag, without its options.
+func tagName(tag string) string {
+	return strings.Split(tag, ",")[0]
+}
+
+// sliceValue returns the slice or array of items, or of a pointer to them.
+func sliceValue(array interface{}) (reflect.Value, error) {
 	value := reflect.ValueOf(array)
-	kind := value.Kind()
-	if kind == reflect.Ptr {
+	if value.Kind() == reflect.Ptr {
 		value = value.Elem()
-		kind = value.Kind()
 	}
 
-	if kind != reflect.Array && kind != reflect.Slice {
-		panic(fmt.Sprintf("can't get len of a value of type %T", array))
+	if value.Kind() != reflect.Array && value.Kind() != reflect.Slice {
+		return value, fmt.Errorf("%w: expected a slice, got %T", ErrInvalidItems, array)
 	}
 
-	return value.Len()
+	return value, nil
 }
 
-func popLastElement(arrayPtr interface{}) (last, remaining interface{}) {
-	ptr := reflect.ValueOf(arrayPtr)
-	if ptr.Kind() != reflect.Ptr {
-		panic(fmt.Sprintf("expected pointer type, got %T", arrayPtr))
+// ItemsSlice returns the slice pointed by items, the destination of a page,
+// or ErrInvalidItems when items isn't a pointer to a slice.
+func ItemsSlice(items interface{}) (reflect.Value, error) {
+	ptr := reflect.ValueOf(items)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("%w: expected a pointer to a slice, got %T", ErrInvalidItems, items)
 	}
 
-	array := ptr.Elem()
-	if array.Kind() != reflect.Array && array.Kind() != reflect.Slice {
-		panic(fmt.Sprintf("can't pop last element of a value of type %T", arrayPtr))
+	return ptr.Elem(), nil
+}
+
+// getLastElementCursor returns the cursor value of the last element, nil if
+// the field is NULL.
+func getLastElementCursor(array interface{}, fieldname string) (interface{}, error) {
+	value, err := getLastElementField(array, fieldname)
+	if err != nil {
+		return nil, err
 	}
 
+	return cursorValue(value), nil
+}
+
+// cursorValue unwraps nullable values (pointers, sql.Null* and null types)
+// and returns nil when they are NULL.
+func cursorValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	value = v.Interface()
+	if _, ok := value.(time.Time); ok {
+		return value
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		return value
+	}
+
+	return value
+}
+
+func getLen(array interface{}) (int, error) {
+	value, err := sliceValue(array)
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Len(), nil
+}
+
+func popLastElement(arrayPtr interface{}) (last, remaining interface{}, err error) {
+	ptr := reflect.ValueOf(arrayPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Slice {
+		return nil, nil, fmt.Errorf("%w: expected a pointer to a slice, got %T", ErrInvalidItems, arrayPtr)
+	}
+
+	array := ptr.Elem()
 	len := array.Len()
 	if len == 0 {
-		return nil, arrayPtr
+		return nil, arrayPtr, nil
 	}
 
 	last = array.Index(len - 1).Interface()
@@ -188,5 +555,101 @@ func popLastElement(arrayPtr interface{}) (last, remaining interface{}) {
 	array.Set(array.Slice(0, len-1))
 	remaining = array.Addr().Interface()
 
-	return last, remaining
+	return last, remaining, nil
+}
+
+// newItems returns a pointer to a new empty slice of the items type, or
+// items when they aren't a pointer.
+func newItems(items interface{}) interface{} {
+	t := reflect.TypeOf(items)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return items
+	}
+	return reflect.New(t.Elem()).Interface()
+}
+
+func reverseElements(arrayPtr interface{}) error {
+	array, err := sliceValue(arrayPtr)
+	if err != nil {
+		return err
+	}
+
+	swap := reflect.Swapper(array.Interface())
+	for i, j := 0, array.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+
+	return nil
+}
+
+func prependElements(arrayPtr interface{}, elements reflect.Value) error {
+	ptr := reflect.ValueOf(arrayPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: expected a pointer to a slice, got %T", ErrInvalidItems, arrayPtr)
+	}
+
+	array := ptr.Elem()
+	array.Set(reflect.AppendSlice(elements, array))
+
+	return nil
+}
+
+// encodeOpaqueCursor returns the opaque cursor of an item, used by Relay
+// connections and page tokens, dates are encoded with a nanosecond precision.
+func encodeOpaqueCursor(options *CursorOptions, item reflect.Value) (string, error) {
+	field, err := getElementField(item, options.StructName)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		value  = cursorValue(field)
+		cursor string
+	)
+
+	if t, ok := value.(time.Time); ok && options.Mode == DateModeCursor {
+		cursor = strconv.FormatInt(t.UnixNano(), 10)
+	} else if value == nil && options.Nulls != "" {
+		key, err := getElementField(item, options.KeyStructName)
+		if err != nil {
+			return "", err
+		}
+		cursor = NullCursorPrefix + formatCursor(cursorValue(key))
+	} else {
+		cursor = formatCursor(value)
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor)), nil
+}
+
+// decodeOpaqueCursor returns the cursor value of an opaque cursor.
+func decodeOpaqueCursor(options *CursorOptions, encoded string) (interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := string(raw)
+	if options.Mode == DateModeCursor && !strings.HasPrefix(cursor, NullCursorPrefix) {
+		nanos, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+
+		if options.Nulls != "" {
+			return NullCursor{Value: time.Unix(0, nanos), KeyDBName: options.KeyDBName, Nulls: options.Nulls}, nil
+		}
+		return time.Unix(0, nanos), nil
+	}
+
+	if options.Nulls != "" {
+		return parseNullCursor(options, cursor), nil
+	}
+
+	value, err := parseCursor(options.Mode, cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return value, nil
 }
@@ -1,9 +1,13 @@
 package paging
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/guregu/null"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -123,52 +127,183 @@ func TestGenerateCursorURI(t *testing.T) {
 }
 
 func Test_GetLastElementField(t *testing.T) {
-	last := getLastElementField(
+	is := assert.New(t)
+
+	last, err := getLastElementField(
 		[]struct{ Fieldname int }{
 			{Fieldname: 1},
 			{Fieldname: 2},
 			{Fieldname: 3}},
 		"Fieldname")
-	assert.New(t).Equal(3, last)
+	is.NoError(err)
+	is.Equal(3, last)
+
+	last, err = getLastElementField([]struct{ Fieldname int }{}, "Fieldname")
+	is.NoError(err)
+	is.Nil(last)
 }
 
-func Test_GetLastElementField_Int(t *testing.T) {
-	defer func() {
-		r := recover()
-		if r == nil {
-			t.Fatalf("expected panic")
-		}
-		expected := "can't get last element of a value of type int"
-		if r.(string) != expected {
-			t.Fatalf("expected %q, got %q", expected, r)
-		}
-	}()
-	getLastElementField(1, "fieldname")
+func Test_GetLastElementField_Lookup(t *testing.T) {
+	is := assert.New(t)
+
+	type Base struct {
+		ID int
+	}
+	type Author struct {
+		Name string
+	}
+	type Post struct {
+		*Base
+		Slug   string `db:"post_slug"`
+		Title  string `json:"title,omitempty"`
+		Author *Author
+	}
+
+	posts := []*Post{{Base: &Base{ID: 1}, Slug: "first", Title: "First", Author: &Author{Name: "jo"}}}
+
+	tests := []struct {
+		fieldname string
+		expected  interface{}
+	}{
+		{"ID", 1},
+		{"Slug", "first"},
+		{"post_slug", "first"},
+		{"title", "First"},
+		{"Author.Name", "jo"},
+	}
+	for _, tt := range tests {
+		value, err := getLastElementField(&posts, tt.fieldname)
+		is.NoError(err, tt.fieldname)
+		is.Equal(tt.expected, value, tt.fieldname)
+	}
+
+	// nil pointers on the way are NULL values
+	posts = []*Post{{}}
+	value, err := getLastElementField(posts, "ID")
+	is.NoError(err)
+	is.Nil(value)
+	value, err = getLastElementField(posts, "Author.Name")
+	is.NoError(err)
+	is.Nil(value)
+
+	value, err = getLastElementField([]map[string]interface{}{{"id": 42}}, "id")
+	is.NoError(err)
+	is.Equal(42, value)
 }
 
-func Test_GetLastElementField_IntSlice(t *testing.T) {
-	defer func() {
-		r := recover()
-		if r == nil {
-			t.Fatalf("expected panic")
-		}
-		expected := "can't get fieldname \"fieldname\" of an element of type int"
-		if r.(string) != expected {
-			t.Fatalf("expected %q, got %q", expected, r)
-		}
-	}()
-	getLastElementField([]int{1}, "fieldname")
+func Test_GetLastElementField_Errors(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := getLastElementField(1, "fieldname")
+	is.True(errors.Is(err, ErrInvalidItems))
+	is.Equal("invalid items: expected a slice, got int", err.Error())
+
+	_, err = getLastElementField([]int{1}, "fieldname")
+	is.True(errors.Is(err, ErrInvalidItems))
+	is.Equal(`invalid items: can't get field "fieldname" of an element of type int`, err.Error())
+
+	_, err = getLastElementField([]struct{ ID int }{{1}}, "Name")
+	is.Equal(`invalid items: no field "Name" in struct { ID int }`, err.Error())
+
+	_, err = getLastElementField([]map[string]int{{"id": 1}}, "name")
+	is.Equal(`invalid items: no key "name" in map[string]int`, err.Error())
+
+	_, err = getLastElementField([]struct{ id int }{{1}}, "id")
+	is.Equal(`invalid items: field "id" is unexported`, err.Error())
 }
 
 func Test_GetLen(t *testing.T) {
-	assert.New(t).Equal(3, getLen([]int{1, 2, 3}))
+	is := assert.New(t)
+
+	len, err := getLen([]int{1, 2, 3})
+	is.NoError(err)
+	is.Equal(3, len)
+
+	_, err = getLen("items")
+	is.True(errors.Is(err, ErrInvalidItems))
 }
 
-func Test_PopLastElement(t *testing.T) {
-	array := &[]int{1, 2, 3}
-	last, remaining := popLastElement(array)
+func TestItemsSlice(t *testing.T) {
+	is := assert.New(t)
 
+	items := []int{1, 2}
+	slice, err := ItemsSlice(&items)
+	is.NoError(err)
+	is.Equal(2, slice.Len())
+	is.True(slice.CanSet())
+
+	for _, items := range []interface{}{nil, items, (*[]int)(nil), &[2]int{}} {
+		_, err = ItemsSlice(items)
+		is.True(errors.Is(err, ErrInvalidItems))
+	}
+}
+
+func Test_PopLastElement(t *testing.T) {
 	is := assert.New(t)
+
+	array := &[]int{1, 2, 3}
+	last, remaining, err := popLastElement(array)
+	is.NoError(err)
 	is.Equal(3, last)
 	is.Equal(&[]int{1, 2}, remaining)
+
+	_, _, err = popLastElement([]int{1})
+	is.True(errors.Is(err, ErrInvalidItems))
+}
+
+func TestGetCursorValueFromRequest(t *testing.T) {
+	is := assert.New(t)
+
+	options := NewOptions()
+	request, _ := http.NewRequest("GET", "http://example.com?since=42", nil)
+	is.Equal(int64(42), GetCursorValueFromRequest(request, options))
+
+	options.CursorOptions.Mode = DateModeCursor
+	is.Equal(time.Unix(42, 0), GetCursorValueFromRequest(request, options))
+
+	options.CursorOptions.Mode = StringModeCursor
+	request, _ = http.NewRequest("GET", "http://example.com?since=my-slug", nil)
+	is.Equal("my-slug", GetCursorValueFromRequest(request, options))
+
+	options.CursorOptions.Mode = UUIDModeCursor
+	request, _ = http.NewRequest("GET", "http://example.com?since=6BA7B810-9DAD-11D1-80B4-00C04FD430C8", nil)
+	is.Equal("6ba7b810-9dad-11d1-80b4-00c04fd430c8", GetCursorValueFromRequest(request, options))
+	is.Equal(CursorType, GetPaginationType(request, options))
+
+	// invalid cursors fallback to the zero value
+	request, _ = http.NewRequest("GET", "http://example.com?since=6ba7b810", nil)
+	is.Equal("", GetCursorValueFromRequest(request, options))
+	is.Equal(OffsetType, GetPaginationType(request, options))
+
+	options.CursorOptions.Mode = ULIDModeCursor
+	request, _ = http.NewRequest("GET", "http://example.com?since=01arz3ndektsv4rrffq69g5fav", nil)
+	is.Equal("01ARZ3NDEKTSV4RRFFQ69G5FAV", GetCursorValueFromRequest(request, options))
+
+	request, _ = http.NewRequest("GET", "http://example.com?since=01ARZ3NDEKTSV4RRFFQ69G5FAU", nil)
+	is.Equal("", GetCursorValueFromRequest(request, options))
+}
+
+func TestGenerateCursorURI_String(t *testing.T) {
+	is := assert.New(t)
+
+	options := NewOptions()
+	options.CursorOptions.Mode = StringModeCursor
+	is.Equal("?limit=10&since=a+b%26c", GenerateCursorURI(int64(10), "a b&c", options))
+}
+
+func Test_CursorValue(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Now()
+	id := 42
+
+	is.Equal(42, cursorValue(42))
+	is.Equal(42, cursorValue(&id))
+	is.Equal(now, cursorValue(now))
+	is.Equal(now, cursorValue(&now))
+	is.Nil(cursorValue((*time.Time)(nil)))
+	is.Equal(int64(42), cursorValue(sql.NullInt64{Int64: 42, Valid: true}))
+	is.Nil(cursorValue(sql.NullInt64{}))
+	is.Equal(now, cursorValue(null.TimeFrom(now)))
+	is.Nil(cursorValue(null.Time{}))
 }